@@ -0,0 +1,253 @@
+package webfmwk
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+)
+
+const (
+	// _listenFDsStart is the first file descriptor passed by systemd.
+	_listenFDsStart = 3
+
+	_envListenPID     = "LISTEN_PID"
+	_envListenFDs     = "LISTEN_FDS"
+	_envListenFDNames = "LISTEN_FDNAMES"
+
+	// _unknownFDName is the name systemd use for the unnamed sockets.
+	_unknownFDName = "unknown"
+)
+
+type (
+	// ListenerOption configure a listener served via ServeListener.
+	ListenerOption func(a *Address)
+
+	// inherited hold the listeners passed by systemd or by the upgraded
+	// process, loaded once.
+	inherited struct {
+		fds           map[string][]net.Listener
+		err           error
+		upgradeErr    error
+		upgrade       upgrade
+		mu            sync.Mutex
+		loaded        bool
+		upgradeLoaded bool
+	}
+)
+
+// ErrNoActivation is returned by SystemdListeners if the process wasn't
+// started via socket activation.
+var ErrNoActivation = errors.New("no socket activation")
+
+// ListenerAddress apply the name and the TLS config of the addr Address.
+// The Address Addr field is only used for display purpose.
+func ListenerAddress(addr Address) ListenerOption {
+	return func(a *Address) { *a = addr }
+}
+
+// ListenerName set the name of the listener.
+func ListenerName(name string) ListenerOption {
+	return func(a *Address) { a.Name = name }
+}
+
+// ListenerTLS serve the listener over TLS, and http2 if enabled.
+func ListenerTLS(cfg tls.Config) ListenerOption {
+	return func(a *Address) { a.TLS = &cfg }
+}
+
+// ServeListener serve the nl pre-opened listener, like a socket inherited
+// from a parent process. The TLS and http2 support are applied as for an
+// Address, see ListenerAddress and ListenerTLS.
+// The method return once the listener is served: use WaitForStop to wait for
+// the server to end. The listener is closed when the server stop.
+func (s *Server) ServeListener(nl net.Listener, opts ...ListenerOption) error {
+	var addr Address
+
+	for _, o := range opts {
+		o(&addr)
+	}
+
+	_, e := s.serveAddress(addr, nl)
+
+	return e
+}
+
+// serveAddress serve the nl listener using the addr config.
+func (s *Server) serveAddress(addr Address, nl net.Listener) (*listener, error) {
+	l := Listener{
+		Name: addr.GetName(), Addr: addr.GetAddr(),
+		Unix: nl.Addr().Network() == "unix", Admin: s.isAdmin(addr),
+	}
+	if l.Addr == "" {
+		l.Addr = nl.Addr().String()
+	}
+
+	s.internalHandler()
+
+	if cfg := addr.GetTLS(); cfg != nil && !cfg.Empty() {
+		ep, _ := addr.Endpoint()
+		l.HTTP2 = s.meta.http2 || ep.HTTP2
+
+		rl, e := tls.NewReloader(cfg, s.Logger(LogTLS), l.HTTP2)
+		if e != nil {
+			return nil, listenError(l, e)
+		}
+
+		return s.serveTLS(l, cfg, rl, nl), nil
+	}
+
+	if s.meta.http2 {
+		return nil, &ListenError{Kind: ErrHTTP2RequireTLS, Err: errors.New("plain http listener"), Listener: l}
+	}
+
+	return s.servePlain(l, nl), nil
+}
+
+// WithSystemdActivation serve the sockets passed by systemd (see
+// systemd.socket(5)). An Address is served on the inherited socket whose
+// FileDescriptorName match its Name instead of binding its Addr, the unnamed
+// Address matching the unnamed sockets. The Address without matching socket
+// are bound as usual, and the sockets matching no Address are closed once
+// Serve started the listeners.
+func WithSystemdActivation() Option {
+	return func(s *Server) {
+		s.meta.systemd = true
+		s.slog.Debug("\t-- systemd socket activation enabled")
+	}
+}
+
+// inheritedListener return the next systemd socket named name, nil if none
+// or if the socket activation isn't enabled.
+func (s *Server) inheritedListener(name string) (net.Listener, error) {
+	if !s.meta.systemd {
+		return nil, nil
+	}
+
+	in := &s.inherited
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if !in.loaded {
+		in.loaded = true
+		if in.fds, in.err = SystemdListeners(); errors.Is(in.err, ErrNoActivation) {
+			in.err = nil
+		}
+	}
+
+	if in.err != nil {
+		return nil, in.err
+	}
+
+	if name == "" {
+		name = _unknownFDName
+	}
+
+	fds := in.fds[name]
+	if len(fds) == 0 {
+		return nil, nil
+	}
+
+	in.fds[name] = fds[1:]
+
+	return fds[0], nil
+}
+
+// closeInherited close the systemd sockets no Address was served on.
+func (s *Server) closeInherited() {
+	in := &s.inherited
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	for name, fds := range in.fds {
+		for _, nl := range fds {
+			s.slog.Warn("closing the unused inherited socket",
+				slog.String("name", name), slog.String("address", nl.Addr().String()))
+			nl.Close()
+		}
+	}
+
+	in.fds = nil
+}
+
+// SystemdListeners return the listeners passed by systemd via the LISTEN_FDS
+// and LISTEN_FDNAMES environment variables, grouped per name. The unnamed
+// sockets are grouped under "unknown". The variables are unset so the child
+// processes don't inherit them. ErrNoActivation is returned if the process
+// wasn't started via socket activation. The caller own the returned
+// listeners, on failure the passed sockets are all closed.
+func SystemdListeners() (map[string][]net.Listener, error) {
+	defer func() {
+		_ = os.Unsetenv(_envListenPID)
+		_ = os.Unsetenv(_envListenFDs)
+		_ = os.Unsetenv(_envListenFDNames)
+	}()
+
+	if pid, ok := os.LookupEnv(_envListenPID); ok && pid != strconv.Itoa(os.Getpid()) {
+		return nil, fmt.Errorf("%w: %s=%s doesn't match the process", ErrNoActivation, _envListenPID, pid)
+	}
+
+	return listenFDs(_listenFDsStart, os.Getenv(_envListenFDs), os.Getenv(_envListenFDNames))
+}
+
+// listenFDs turn the count file descriptors starting at start into listeners.
+func listenFDs(start int, count, names string) (map[string][]net.Listener, error) {
+	if count == "" {
+		return nil, ErrNoActivation
+	}
+
+	n, e := strconv.Atoi(count)
+	if e != nil || n < 0 {
+		return nil, fmt.Errorf("invalid %s value %q", _envListenFDs, count)
+	}
+
+	var (
+		ret   = make(map[string][]net.Listener, n)
+		split = strings.Split(names, ":")
+	)
+
+	for i := 0; i < n; i++ {
+		name := _unknownFDName
+		if i < len(split) && split[i] != "" {
+			name = split[i]
+		}
+
+		f := os.NewFile(uintptr(start+i), name)
+
+		// FileListener dup the fd, the close-on-exec flag being set on the copy
+		nl, e := net.FileListener(f)
+		f.Close()
+
+		if e != nil {
+			closeFDs(ret, start+i+1, start+n)
+
+			return nil, fmt.Errorf("socket %q (fd %d): %w", name, start+i, e)
+		}
+
+		ret[name] = append(ret[name], nl)
+	}
+
+	return ret, nil
+}
+
+// closeFDs close the ret listeners and the file descriptors from start to
+// end.
+func closeFDs(ret map[string][]net.Listener, start, end int) {
+	for _, fds := range ret {
+		for _, nl := range fds {
+			nl.Close()
+		}
+	}
+
+	for fd := start; fd < end; fd++ {
+		os.NewFile(uintptr(fd), "").Close()
+	}
+}
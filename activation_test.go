@@ -0,0 +1,127 @@
+package webfmwk
+
+import (
+	"context"
+	fmtls "crypto/tls"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeListener(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	s.GET("/ping", func(c Context) error { return c.JSONOk(_pong) })
+
+	defer func() { require.Nil(t, s.ShutdownAndWait()) }()
+
+	plain, e := net.Listen("tcp4", "127.0.0.1:0")
+	require.Nil(t, e)
+
+	secure, e := net.Listen("tcp4", "127.0.0.1:0")
+	require.Nil(t, e)
+
+	require.Nil(t, s.ServeListener(plain, ListenerName("plain")))
+	require.Nil(t, s.ServeListener(secure, ListenerName("secure"), ListenerTLS(tls.Config{
+		Cert: "./example/ssl.crt", Key: "./example/ssl.key", Insecure: true,
+	})))
+
+	assert.ElementsMatch(t, []Listener{
+		{Name: "plain", Addr: plain.Addr().String()},
+		{Name: "secure", Addr: secure.Addr().String(), TLS: true},
+	}, s.Listeners())
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &fmtls.Config{InsecureSkipVerify: true}, //nolint:gosec
+	}}
+
+	for _, uri := range []string{
+		"http://" + plain.Addr().String() + "/ping",
+		"https://" + secure.Addr().String() + "/ping",
+	} {
+		resp, e := client.Get(uri) //nolint:noctx
+		require.Nil(t, e, uri)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, uri)
+	}
+
+	t.Log("invalid tls config")
+	{
+		nl, e := net.Listen("tcp4", "127.0.0.1:0")
+		require.Nil(t, e)
+
+		defer nl.Close()
+
+		e = s.ServeListener(nl, ListenerTLS(tls.Config{Cert: "missing", Key: "missing"}))
+		assert.ErrorIs(t, e, ErrTLSCertificate)
+	}
+}
+
+func TestSystemdActivation(t *testing.T) {
+	_, e := listenFDs(_listenFDsStart, "", "")
+	assert.ErrorIs(t, e, ErrNoActivation)
+
+	_, e = listenFDs(_listenFDsStart, "nope", "")
+	assert.NotNil(t, e)
+
+	nl, e := net.Listen("tcp4", "127.0.0.1:0")
+	require.Nil(t, e)
+
+	f, e := nl.(*net.TCPListener).File()
+	require.Nil(t, e)
+	require.Nil(t, nl.Close())
+
+	// listenFDs take the ownership of the fd
+	fd, e := syscall.Dup(int(f.Fd()))
+	require.Nil(t, e)
+	require.Nil(t, f.Close())
+
+	fds, e := listenFDs(fd, "1", "web")
+	require.Nil(t, e)
+	require.Len(t, fds["web"], 1)
+
+	unused, e := net.Listen("tcp4", "127.0.0.1:0")
+	require.Nil(t, e)
+
+	fds["unused"] = []net.Listener{unused}
+
+	s, e := InitServer(WithSystemdActivation())
+	require.Nil(t, e)
+
+	s.GET("/ping", func(c Context) error { return c.JSONOk(_pong) })
+
+	// skip the environment lookup
+	s.inherited.loaded, s.inherited.fds = true, fds
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error)
+		addr        = fds["web"][0].Addr().String()
+	)
+
+	go func() { done <- s.Serve(ctx, Address{Name: "web"}) }()
+
+	require.Eventually(t, func() bool { return len(s.Listeners()) == 1 },
+		time.Second, 10*time.Millisecond)
+	assert.Equal(t, addr, s.Listeners()[0].Addr)
+
+	resp, e := http.Get("http://" + addr + "/ping") //nolint:noctx
+	require.Nil(t, e)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	t.Log("the socket matching no address is closed")
+
+	_, e = net.Dial("tcp4", unused.Addr().String())
+	assert.NotNil(t, e)
+
+	cancel()
+	require.Nil(t, <-done)
+}
@@ -35,7 +35,8 @@ type (
 		SameAs(in IAddress) bool
 	}
 
-	// Address implement the IAddress interface
+	// Address implement the IAddress interface.
+	// See Endpoint for the accepted Addr formats.
 	Address struct {
 		// TLS implement IAddress, tlsConfig  implement the TLSConfig interface.
 		TLS  *tls.Config `json:"tls,omitempty" mapstructure:"tls,omitempty"`
@@ -134,14 +135,40 @@ func (a Address) String() string {
 	return fmt.Sprintf("name: %q\naddr: %q", a.Name, a.Addr)
 }
 
-// IsOk implement the IAddress interface
+// IsUnixPath implement the IAddress interface
 func (a Address) IsUnixPath() bool {
 	return strings.HasPrefix(a.Addr, _unixSocketPrefix)
 }
 
-// IsOk implement the IAddress interface
+// IsOk implement the IAddress interface. See Validate for the details.
 func (a Address) IsOk() bool {
-	return a.Addr != ""
+	return a.Validate() == nil
+}
+
+// Validate return a detailed error if the address is invalid: malformed
+// Addr, unknown option, or tls config mismatching the scheme.
+// The returned errors wrap ErrInvalidAddress.
+func (a Address) Validate() error {
+	ep, e := a.Endpoint()
+	if e != nil {
+		return e
+	}
+
+	hasTLS := a.TLS != nil && !a.TLS.Empty()
+
+	switch {
+	case ep.Scheme == _schemeHTTPS && !hasTLS:
+		return fmt.Errorf("%w %q: the https scheme require a tls config", ErrInvalidAddress, a.Addr)
+	case hasTLS && (ep.Scheme == _schemeHTTP || ep.IsUnix()):
+		return fmt.Errorf("%w %q: tls config on a %s address", ErrInvalidAddress, a.Addr, ep.Scheme)
+	}
+
+	return nil
+}
+
+// Endpoint return the parsed form of the Addr field.
+func (a Address) Endpoint() (Endpoint, error) {
+	return ParseEndpoint(a.Addr)
 }
 
 // GetAddr implement the IAddress interface
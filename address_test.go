@@ -30,7 +30,8 @@ func TestAddress(t *testing.T) {
 
 	requirer.Equal("Testing", addr.GetAddr())
 	requirer.Equal("oops", addr.GetName())
-	requirer.True(addr.IsOk())
+	requirer.False(addr.IsOk())
+	requirer.ErrorIs(addr.Validate(), ErrInvalidAddress)
 
 	requirer.Equal(
 		"\n\t -!- name: \"oops\"\n\t -!- addr: \"Testing\"\n"+
@@ -56,6 +57,37 @@ func TestAddress(t *testing.T) {
 		}.String())
 }
 
+func TestAddressValidate(t *testing.T) {
+	var (
+		cfg   = &tls.Config{Cert: "some/cert", Key: "some/key"}
+		tests = map[string]struct {
+			addr Address
+			ok   bool
+		}{
+			"bare":              {addr: Address{Addr: ":4242"}, ok: true},
+			"bare tls":          {addr: Address{Addr: "127.0.0.1:4242", TLS: cfg}, ok: true},
+			"https":             {addr: Address{Addr: "https://[::]:443?h2=1", TLS: cfg}, ok: true},
+			"unix":              {addr: Address{Addr: "unix:///run/app.sock?mode=0660"}, ok: true},
+			"missing port":      {addr: Address{Addr: "localhost"}},
+			"https without tls": {addr: Address{Addr: "https://0.0.0.0:443"}},
+			"http with tls":     {addr: Address{Addr: "http://0.0.0.0:80", TLS: cfg}},
+			"unix with tls":     {addr: Address{Addr: "unix:///run/app.sock", TLS: cfg}},
+		}
+	)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := test.addr.Validate()
+			require.Equal(t, test.ok, e == nil, e)
+			require.Equal(t, test.ok, test.addr.IsOk())
+
+			if !test.ok {
+				require.ErrorIs(t, e, ErrInvalidAddress)
+			}
+		})
+	}
+}
+
 func TestAddressSameAs(t *testing.T) {
 	addrs := Addresses{{
 		Addr: "uno",
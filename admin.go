@@ -0,0 +1,257 @@
+package webfmwk
+
+import (
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sort"
+
+	"github.com/fasthttp/router"
+	"github.com/valyala/fasthttp/pprofhandler"
+)
+
+const (
+	_adminName = "admin"
+
+	_routesEndpoint    = "/routes"
+	_buildEndpoint     = "/build"
+	_runtimeEndpoint   = "/runtime"
+	_gcEndpoint        = "/runtime/gc"
+	_tlsReloadEndpoint = "/runtime/tls/reload"
+	_workersEndpoint   = "/workers"
+	_loggersEndpoint   = "/loggers"
+	_loggerEndpoint    = "/loggers/{name}"
+)
+
+type (
+	// BuildInfo hold the build information exposed by the admin `/build`
+	// endpoint.
+	BuildInfo struct {
+		Settings  map[string]string `json:"settings,omitempty"`
+		GoVersion string            `json:"go_version"`
+		Path      string            `json:"path"`
+		Version   string            `json:"version"`
+	}
+
+	// RuntimeInfo hold the runtime statistics exposed by the admin `/runtime`
+	// endpoint.
+	RuntimeInfo struct {
+		Goroutines  int    `json:"goroutines"`
+		GOMAXPROCS  int    `json:"gomaxprocs"`
+		NumCPU      int    `json:"num_cpu"`
+		HeapAlloc   uint64 `json:"heap_alloc"`
+		HeapObjects uint64 `json:"heap_objects"`
+		Sys         uint64 `json:"sys"`
+		NumGC       uint32 `json:"num_gc"`
+		InFlight    int64  `json:"in_flight"`
+		Connections int    `json:"connections"`
+	}
+
+	// RouteInfo hold a route exposed by the admin `/routes` endpoint.
+	RouteInfo struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+	}
+
+	// LogLevelUpdate hold the payload of the admin `PUT /loggers/{name}`
+	// endpoint, like `{"level": "debug"}`.
+	LogLevelUpdate struct {
+		Level slog.Level `json:"level"`
+	}
+)
+
+// WithAdminAddress serve the operational endpoints on the dedicated addr
+// listener, started by Serve and Run next to the public addresses. Protect it
+// via an mTLS config (see tls.Config.Level). The listener is named "admin"
+// unless addr is named, and it's left untouched by Reconfigure.
+//
+// The admin listener serve, without prefix nor handlers:
+//   - the pprof endpoints (see EnablePprof for the path)
+//   - /ping, /livez, /readyz and /healthz (see WithHealthChecks)
+//   - /routes, the public routes
+//   - /build, the binary build information
+//   - /runtime, the runtime statistics
+//   - /workers and /jobs, the workers and jobs status
+//   - /loggers, the loggers level, and PUT /loggers/{name} to change one of
+//     them (see SetLogLevel)
+//   - POST /runtime/gc and POST /runtime/tls/reload, to force a garbage
+//     collection and a TLS material reload
+//   - the DocHandler registered via WithAdminHandlers, like the metrics one
+//
+// Once set, none of those endpoints is registered on the public listeners.
+func WithAdminAddress(addr Address) Option {
+	return func(s *Server) {
+		if addr.Name == "" {
+			addr.Name = _adminName
+		}
+
+		s.meta.admin = &addr
+		s.slog.Debug("\t-- admin address loaded", slog.String("address", addr.Addr))
+	}
+}
+
+// WithAdminHandlers register DocHandler served by the admin listener only.
+func WithAdminHandlers(handler ...DocHandler) Option {
+	return func(s *Server) {
+		s.meta.adminHandlers = append(s.meta.adminHandlers, handler...)
+		s.slog.Debug("\t-- admin handlers loaded")
+	}
+}
+
+// isAdmin return true if addr is the admin address.
+func (s *Server) isAdmin(addr Address) bool {
+	return s.meta.admin != nil &&
+		addr.GetAddr() == s.meta.admin.GetAddr() && addr.GetName() == s.meta.admin.GetName()
+}
+
+// hasAdmin return true if the operational endpoints are served by an
+// admin listener.
+func (s *Server) hasAdmin() bool { return s.meta.admin != nil }
+
+// GetAdminRouter create the fasthttp/router.Router of the admin listener.
+func (s *Server) GetAdminRouter() *router.Router {
+	var (
+		r      = router.New()
+		routes = s.publicRoutes()
+		build  = buildInfo()
+	)
+
+	r.HandleMethodNotAllowed = true
+	r.NotFound, r.MethodNotAllowed = s.CustomHandler(handleNotFound), s.CustomHandler(handleNotAllowed)
+
+	for i := range s.meta.adminHandlers {
+		h := s.meta.adminHandlers[i]
+		s.slog.Info("load admin handler", slog.String("name", h.Name))
+		r.ANY(h.Path, s.CustomHandler(h.H))
+	}
+
+	r.GET(s.meta.pprofPath, pprofhandler.PprofHandler)
+
+	r.GET(_pingEndpoint, s.CustomHandler(s.pingHandler))
+	r.GET(_livezEndpoint, s.CustomHandler(s.healthHandler(Liveness)))
+	r.GET(_readyzEndpoint, s.CustomHandler(s.healthHandler(Readiness|Startup)))
+	r.GET(_healthzEndpoint, s.CustomHandler(s.healthHandler(0)))
+
+	r.GET(_routesEndpoint, s.CustomHandler(func(c Context) error { return c.JSONOk(routes) }))
+	r.GET(_buildEndpoint, s.CustomHandler(func(c Context) error { return c.JSONOk(build) }))
+	r.GET(_workersEndpoint, s.CustomHandler(func(c Context) error { return c.JSONOk(s.Workers()) }))
+	r.GET(_jobsEndpoint, s.CustomHandler(func(c Context) error { return c.JSONOk(s.Jobs()) }))
+	r.GET(_runtimeEndpoint, s.CustomHandler(func(c Context) error { return c.JSONOk(s.runtimeInfo()) }))
+
+	r.GET(_loggersEndpoint, s.CustomHandler(func(c Context) error { return c.JSONOk(s.LogLevels()) }))
+	r.PUT(_loggerEndpoint, s.CustomHandler(handleHandlerError(s.setLogLevelHandler)))
+
+	r.POST(_gcEndpoint, s.CustomHandler(func(c Context) error {
+		s.slog.Info("admin: forcing a garbage collection")
+		runtime.GC()
+		debug.FreeOSMemory()
+
+		return c.JSONOk(s.runtimeInfo())
+	}))
+
+	r.POST(_tlsReloadEndpoint, s.CustomHandler(handleHandlerError(func(c Context) error {
+		s.slog.Info("admin: reloading the tls material")
+
+		if e := s.ReloadTLS(); e != nil {
+			return NewInternal(NewError(e.Error()))
+		}
+
+		return c.JSONNoContent()
+	})))
+
+	return r
+}
+
+// setLogLevelHandler change the level of the logger named by the route.
+func (s *Server) setLogLevelHandler(c Context) error {
+	var (
+		name = c.GetVar("name")
+		req  LogLevelUpdate
+	)
+
+	if e := c.FetchContent(&req); e != nil {
+		return e
+	}
+
+	if e := s.SetLogLevel(name, req.Level); e != nil {
+		return NewNotFound(NewError(e.Error()))
+	}
+
+	s.slog.Info("admin: log level updated", slog.String("logger", name), slog.String("level", req.Level.String()))
+
+	return c.JSONOk(s.LogLevels())
+}
+
+// runtimeInfo return the current runtime statistics.
+func (s *Server) runtimeInfo() RuntimeInfo {
+	var ms runtime.MemStats
+
+	runtime.ReadMemStats(&ms)
+
+	return RuntimeInfo{
+		Goroutines:  runtime.NumGoroutine(),
+		GOMAXPROCS:  runtime.GOMAXPROCS(0),
+		NumCPU:      runtime.NumCPU(),
+		HeapAlloc:   ms.HeapAlloc,
+		HeapObjects: ms.HeapObjects,
+		Sys:         ms.Sys,
+		NumGC:       ms.NumGC,
+		InFlight:    s.inFlight.Load(),
+		Connections: s.conns.len(),
+	}
+}
+
+// publicRoutes return the routes served by the public listeners once the
+// admin listener is set, sorted by path and method.
+func (s *Server) publicRoutes() []RouteInfo {
+	var ret []RouteInfo
+
+	for i := range s.meta.docHandlers {
+		ret = append(ret, RouteInfo{Method: router.MethodWild, Path: s.meta.prefix + s.meta.docHandlers[i].Path})
+	}
+
+	if s.meta.socketIOHF || s.meta.socketIOH {
+		ret = append(ret, RouteInfo{Method: router.MethodWild, Path: s.meta.socketIOPath})
+	}
+
+	if s.meta.openapi {
+		ret = append(ret, RouteInfo{Method: GET, Path: s.meta.prefix + s.meta.openapiPath})
+	}
+
+	for prefix, routes := range s.meta.routes {
+		for i := range routes {
+			ret = append(ret, RouteInfo{Method: routes[i].Verbe, Path: prefix + routes[i].Path})
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Path == ret[j].Path {
+			return ret[i].Method < ret[j].Method
+		}
+
+		return ret[i].Path < ret[j].Path
+	})
+
+	return ret
+}
+
+// buildInfo return the binary build information.
+func buildInfo() BuildInfo {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return BuildInfo{GoVersion: runtime.Version()}
+	}
+
+	ret := BuildInfo{
+		GoVersion: bi.GoVersion,
+		Path:      bi.Main.Path,
+		Version:   bi.Main.Version,
+		Settings:  make(map[string]string, len(bi.Settings)),
+	}
+
+	for _, s := range bi.Settings {
+		ret.Settings[s.Key] = s.Value
+	}
+
+	return ret
+}
@@ -0,0 +1,140 @@
+package webfmwk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6/openapi"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAddress(t *testing.T) {
+	var (
+		public, admin = freeAddr(t), freeAddr(t)
+		ctx, cancel   = context.WithCancel(context.Background())
+		done          = make(chan error, 1)
+	)
+
+	defer cancel()
+
+	s, e := InitServer(
+		SetPrefix("/api"),
+		CheckIsUp(),
+		EnablePprof(),
+		WithHealthChecks(HealthCheck{Name: "db", Check: func(context.Context) error { return nil }}),
+		WithAdminAddress(Address{Addr: admin}),
+		WithAdminHandlers(DocHandler{Name: "metrics", Path: "/metrics", H: func(c Context) error {
+			return c.JSONOk("metrics")
+		}}))
+	require.Nil(t, e)
+
+	s.GET("/hello", func(c Context) error { return c.JSONOk("hello") })
+
+	go func() { done <- s.Serve(ctx, Address{Addr: public}) }()
+
+	wctx, wcancel := context.WithTimeout(ctx, 5*time.Second)
+	defer wcancel()
+
+	require.Nil(t, s.WaitReady(wctx))
+	<-s.Ready(_adminName)
+
+	get := func(t *testing.T, method, uri string) (int, []byte) {
+		t.Helper()
+
+		req, e := http.NewRequestWithContext(ctx, method, uri, http.NoBody)
+		require.Nil(t, e)
+
+		resp, e := http.DefaultClient.Do(req)
+		require.Nil(t, e)
+
+		defer resp.Body.Close()
+
+		body, e := io.ReadAll(resp.Body)
+		require.Nil(t, e)
+
+		return resp.StatusCode, body
+	}
+
+	t.Run("public", func(t *testing.T) {
+		code, _ := get(t, http.MethodGet, "http://"+public+"/api/hello")
+		assert.Equal(t, http.StatusOK, code)
+
+		for _, path := range []string{"/api/ping", "/api/livez", "/api/debug/pprof/", "/metrics", "/routes", "/runtime"} {
+			code, _ := get(t, http.MethodGet, "http://"+public+path)
+			assert.Equal(t, http.StatusNotFound, code, path)
+		}
+	})
+
+	t.Run("admin", func(t *testing.T) {
+		for _, path := range []string{"/ping", "/livez", "/readyz?verbose", "/healthz", "/debug/pprof/", "/metrics", "/workers", "/jobs"} {
+			code, _ := get(t, http.MethodGet, "http://"+admin+path)
+			assert.Equal(t, http.StatusOK, code, path)
+		}
+
+		code, _ := get(t, http.MethodGet, "http://"+admin+"/api/hello")
+		assert.Equal(t, http.StatusNotFound, code)
+
+		var routes []RouteInfo
+
+		_, body := get(t, http.MethodGet, "http://"+admin+"/routes")
+		require.Nil(t, json.Unmarshal(body, &routes))
+		assert.Contains(t, routes, RouteInfo{Method: http.MethodGet, Path: "/api/hello"})
+
+		var build BuildInfo
+
+		_, body = get(t, http.MethodGet, "http://"+admin+"/build")
+		require.Nil(t, json.Unmarshal(body, &build))
+		assert.NotEmpty(t, build.GoVersion)
+
+		var rt RuntimeInfo
+
+		code, body = get(t, http.MethodPost, "http://"+admin+"/runtime/gc")
+		assert.Equal(t, http.StatusOK, code)
+		require.Nil(t, json.Unmarshal(body, &rt))
+		assert.Positive(t, rt.Goroutines)
+		assert.Positive(t, rt.NumGC)
+
+		code, _ = get(t, http.MethodPost, "http://"+admin+"/runtime/tls/reload")
+		assert.Equal(t, http.StatusNoContent, code)
+	})
+
+	t.Run("reconfigure", func(t *testing.T) {
+		report := s.Reconfigure(Addresses{})
+		require.Nil(t, report.Err())
+		assert.Len(t, report.Stopped, 1)
+
+		code, _ := get(t, http.MethodGet, "http://"+admin+"/ping")
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	cancel()
+	require.Nil(t, <-done)
+}
+
+func TestAdminRoutes(t *testing.T) {
+	s, e := InitServer(
+		SetPrefix("/api"),
+		CheckIsUp(),
+		WithOpenAPI("/openapi.json", openapi.Info{Title: "test", Version: "1.0.0"}),
+		WithDocHandlers(DocHandler{Name: "doc", Path: "/doc", H: func(c Context) error { return c.JSONOk("doc") }}),
+		WithAdminAddress(Address{Addr: "127.0.0.1:0"}))
+	require.Nil(t, e)
+
+	s.GET("/hello", func(c Context) error { return c.JSONOk("hello") })
+	s.Group("/users").POST("/{id}", func(c Context) error { return c.JSONNoContent() })
+
+	var served []RouteInfo
+
+	for method, paths := range s.GetRouter().List() {
+		for _, path := range paths {
+			served = append(served, RouteInfo{Method: method, Path: path})
+		}
+	}
+
+	assert.ElementsMatch(t, served, s.publicRoutes())
+}
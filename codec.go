@@ -0,0 +1,267 @@
+package webfmwk
+
+import (
+	"bytes"
+	"encoding/xml"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/segmentio/encoding/json"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MIMEJSON is the JSON codec content type.
+	MIMEJSON = "application/json"
+	// MIMEXML is the XML codec content type.
+	MIMEXML = "application/xml"
+	// MIMEMsgPack is the MessagePack codec content type.
+	MIMEMsgPack = "application/msgpack"
+	// MIMECBOR is the CBOR codec content type.
+	MIMECBOR = "application/cbor"
+	// MIMEYAML is the YAML codec content type.
+	MIMEYAML = "application/yaml"
+)
+
+type (
+	// Codec interface is used to encode and decode the payloads of a
+	// specific content type.
+	Codec interface {
+		// ContentType return the mime type handled by the codec.
+		ContentType() string
+
+		// Aliases return the other mime types handled by the codec, if any.
+		Aliases() []string
+
+		// Marshal encode v.
+		Marshal(v interface{}) ([]byte, error)
+
+		// Unmarshal decode data into v.
+		Unmarshal(data []byte, v interface{}) error
+	}
+
+	// codecs hold the codecs registered by a server, the first one is the
+	// default one.
+	codecs []Codec
+
+	jsonCodec    struct{}
+	xmlCodec     struct{}
+	msgpackCodec struct{}
+	cborCodec    struct{}
+	yamlCodec    struct{}
+)
+
+var (
+	// ErrUnsupportedContentType is returned when no codec match the request content type.
+	ErrUnsupportedContentType = NewNotAcceptable(NewError("Content-Type is not supported"))
+
+	// ErrNotAcceptable is returned when no codec match the request Accept header.
+	ErrNotAcceptable = NewNotAcceptable(NewError("no acceptable content type"))
+)
+
+// JSONCodec return the json Codec.
+func JSONCodec() Codec { return jsonCodec{} }
+
+// XMLCodec return the xml Codec.
+func XMLCodec() Codec { return xmlCodec{} }
+
+// MsgPackCodec return the MessagePack Codec. The json struct tags are used.
+func MsgPackCodec() Codec { return msgpackCodec{} }
+
+// CBORCodec return the CBOR Codec. The json struct tags are used if no cbor ones are defined.
+func CBORCodec() Codec { return cborCodec{} }
+
+// YAMLCodec return the YAML Codec.
+func YAMLCodec() Codec { return yamlCodec{} }
+
+func defaultCodecs() codecs {
+	return codecs{JSONCodec(), XMLCodec(), MsgPackCodec(), CBORCodec(), YAMLCodec()}
+}
+
+func (jsonCodec) ContentType() string                     { return MIMEJSON }
+func (jsonCodec) Aliases() []string                       { return nil }
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)   { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(b []byte, v interface{}) error { return json.Unmarshal(b, v) }
+
+func (xmlCodec) ContentType() string                     { return MIMEXML }
+func (xmlCodec) Aliases() []string                       { return []string{"text/xml"} }
+func (xmlCodec) Marshal(v interface{}) ([]byte, error)   { return xml.Marshal(v) }
+func (xmlCodec) Unmarshal(b []byte, v interface{}) error { return xml.Unmarshal(b, v) }
+
+func (msgpackCodec) ContentType() string { return MIMEMsgPack }
+func (msgpackCodec) Aliases() []string {
+	return []string{"application/x-msgpack", "application/vnd.msgpack"}
+}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		enc = msgpack.NewEncoder(&buf)
+	)
+
+	enc.SetCustomStructTag("json")
+
+	if e := enc.Encode(v); e != nil {
+		return nil, e
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(b []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(b))
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(v)
+}
+
+func (cborCodec) ContentType() string                     { return MIMECBOR }
+func (cborCodec) Aliases() []string                       { return nil }
+func (cborCodec) Marshal(v interface{}) ([]byte, error)   { return cbor.Marshal(v) }
+func (cborCodec) Unmarshal(b []byte, v interface{}) error { return cbor.Unmarshal(b, v) }
+
+func (yamlCodec) ContentType() string { return MIMEYAML }
+func (yamlCodec) Aliases() []string {
+	return []string{"application/x-yaml", "text/yaml", "text/x-yaml"}
+}
+func (yamlCodec) Marshal(v interface{}) ([]byte, error)   { return yaml.Marshal(v) }
+func (yamlCodec) Unmarshal(b []byte, v interface{}) error { return yaml.Unmarshal(b, v) }
+
+// handle return true if the c codec handle the mt mime type.
+func handle(c Codec, mt string) bool {
+	if strings.EqualFold(c.ContentType(), mt) {
+		return true
+	}
+
+	for _, a := range c.Aliases() {
+		if strings.EqualFold(a, mt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// handlePrefix return true if the c codec handle a mime type of the prefix family.
+func handlePrefix(c Codec, prefix string) bool {
+	if strings.HasPrefix(c.ContentType(), prefix) {
+		return true
+	}
+
+	for _, a := range c.Aliases() {
+		if strings.HasPrefix(a, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// register add the c codecs, replacing the ones handling the same content type.
+func (cs codecs) register(c ...Codec) codecs {
+	for i := range c {
+		found := false
+
+		for j := range cs {
+			if cs[j].ContentType() == c[i].ContentType() {
+				cs[j], found = c[i], true
+
+				break
+			}
+		}
+
+		if !found {
+			cs = append(cs, c[i])
+		}
+	}
+
+	return cs
+}
+
+// forContentType return the codec matching the ctype Content-Type header value.
+func (cs codecs) forContentType(ctype string) (Codec, bool) {
+	mt, _, e := mime.ParseMediaType(ctype)
+	if e != nil {
+		return nil, false
+	}
+
+	for i := range cs {
+		if handle(cs[i], mt) {
+			return cs[i], true
+		}
+	}
+
+	return nil, false
+}
+
+type acceptRange struct {
+	mt string
+	q  float64
+}
+
+// parseAccept return the Accept header media ranges ordered by preference.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, e := mime.ParseMediaType(strings.TrimSpace(part))
+		if e != nil {
+			continue
+		}
+
+		q := 1.0
+
+		if v, ok := params["q"]; ok {
+			if q, e = strconv.ParseFloat(v, 64); e != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			ranges = append(ranges, acceptRange{mt, q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	return ranges
+}
+
+// forAccept return the preferred codec matching the accept Accept header value.
+// The default codec is returned if the header is empty.
+func (cs codecs) forAccept(accept string) (Codec, bool) {
+	if len(cs) == 0 {
+		return nil, false
+	} else if accept == "" {
+		return cs[0], true
+	}
+
+	for _, r := range parseAccept(accept) {
+		switch {
+		case r.mt == "*/*":
+			return cs[0], true
+
+		case strings.HasSuffix(r.mt, "/*"):
+			prefix := strings.TrimSuffix(r.mt, "*")
+
+			for i := range cs {
+				if handlePrefix(cs[i], prefix) {
+					return cs[i], true
+				}
+			}
+
+		default:
+			for i := range cs {
+				if handle(cs[i], r.mt) {
+					return cs[i], true
+				}
+			}
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,138 @@
+package webfmwk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type codecPayload struct {
+	Name  string `json:"name"  xml:"name"  yaml:"name"`
+	Value int    `json:"value" xml:"value" yaml:"value"`
+}
+
+func TestCodecsForAccept(t *testing.T) {
+	cs := defaultCodecs()
+
+	tests := map[string]struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		"empty":         {accept: "", want: MIMEJSON, ok: true},
+		"any":           {accept: "*/*", want: MIMEJSON, ok: true},
+		"xml":           {accept: "application/xml", want: MIMEXML, ok: true},
+		"xml alias":     {accept: "text/xml", want: MIMEXML, ok: true},
+		"yaml":          {accept: "application/x-yaml", want: MIMEYAML, ok: true},
+		"quality":       {accept: "application/xml;q=0.5, application/cbor", want: MIMECBOR, ok: true},
+		"wildcard":      {accept: "text/html, text/*", want: MIMEXML, ok: true},
+		"unknown":       {accept: "text/html", ok: false},
+		"refused":       {accept: "application/msgpack;q=0", ok: false},
+		"unknown first": {accept: "text/html, application/msgpack;q=0.1", want: MIMEMsgPack, ok: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, ok := cs.forAccept(test.accept)
+			require.Equal(t, test.ok, ok)
+
+			if ok {
+				assert.Equal(t, test.want, c.ContentType())
+			}
+		})
+	}
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	in := codecPayload{Name: "webfmwk", Value: 42}
+
+	for _, c := range defaultCodecs() {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			var out codecPayload
+
+			b, e := c.Marshal(in)
+			require.Nil(t, e)
+			require.Nil(t, c.Unmarshal(b, &out))
+			assert.Equal(t, in, out)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	s.GET("/render", func(c Context) error {
+		return c.Render(http.StatusOK, codecPayload{Name: "webfmwk", Value: 42})
+	})
+
+	s.POST("/fetch", func(c Context) error {
+		var p codecPayload
+		if e := c.FetchContent(&p); e != nil {
+			return e
+		}
+
+		return c.Render(http.StatusCreated, p)
+	})
+
+	do := func(method, uri string, headers map[string]string, body []byte) *fasthttp.RequestCtx {
+		fc := &fasthttp.RequestCtx{}
+		fc.Request.Header.SetMethod(method)
+		fc.Request.SetRequestURI(uri)
+		fc.Request.SetBody(body)
+
+		for k, v := range headers {
+			fc.Request.Header.Set(k, v)
+		}
+
+		s.GetRouter().Handler(fc)
+
+		return fc
+	}
+
+	t.Log("default to json")
+	{
+		fc := do(GET, "/render", nil, nil)
+		assert.Equal(t, http.StatusOK, fc.Response.StatusCode())
+		assert.Equal(t, `{"name":"webfmwk","value":42}`, string(fc.Response.Body()))
+	}
+
+	t.Log("pick the encoder from the Accept header")
+	{
+		fc := do(GET, "/render", map[string]string{"Accept": "application/yaml"}, nil)
+		assert.Equal(t, http.StatusOK, fc.Response.StatusCode())
+		assert.Equal(t, MIMEYAML, string(fc.Response.Header.ContentType()))
+		assert.Equal(t, "name: webfmwk\nvalue: 42\n", string(fc.Response.Body()))
+	}
+
+	t.Log("406 on Accept mismatch")
+	{
+		fc := do(GET, "/render", map[string]string{"Accept": "text/html"}, nil)
+		assert.Equal(t, http.StatusNotAcceptable, fc.Response.StatusCode())
+	}
+
+	t.Log("pick the decoder from the Content-Type header")
+	{
+		b, e := MsgPackCodec().Marshal(codecPayload{Name: "msgpack", Value: 1})
+		require.Nil(t, e)
+
+		fc := do(POST, "/fetch", map[string]string{
+			"Content-Type": MIMEMsgPack,
+			"Accept":       MIMEXML,
+		}, b)
+		assert.Equal(t, http.StatusCreated, fc.Response.StatusCode())
+		assert.Equal(t, `<codecPayload><name>msgpack</name><value>1</value></codecPayload>`,
+			string(fc.Response.Body()))
+	}
+
+	t.Log("reject unsupported Content-Type")
+	{
+		fc := do(POST, "/fetch", map[string]string{"Content-Type": "text/plain"}, []byte("hello"))
+		assert.Equal(t, http.StatusNotAcceptable, fc.Response.StatusCode())
+		assert.Equal(t, `{"message":"Content-Type is not supported","status":406}`,
+			string(fc.Response.Body()))
+	}
+}
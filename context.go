@@ -2,7 +2,6 @@ package webfmwk
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -17,7 +16,8 @@ const _prettyTag = "pretty"
 type (
 	// InputHandling interface introduce I/O actions.
 	InputHandling interface {
-		// FetchContent extract the json content from the body into the content interface.
+		// FetchContent extract the body content into the content interface.
+		// The decoder is picked from the request Content-Type header.
 		FetchContent(content interface{}) ErrorHandled
 
 		// Validate is used to validate a content of the content params.
@@ -42,6 +42,11 @@ type (
 
 		// GetStructuredLogger return context' structured logger.
 		GetStructuredLogger() *slog.Logger
+
+		// GetLogger return the name server logger (see Server.Logger), logging
+		// all the levels if the request carry a valid debug token (see
+		// WithDebugHeader).
+		GetLogger(name string) *slog.Logger
 	}
 
 	// Context interface implement the context used in this project.
@@ -56,12 +61,25 @@ type (
 		// GetContext return the request context.Context.
 		GetContext() context.Context
 
+		// SetContext replace the request context.Context, ex: to attach
+		// a value (trace span, deadline, ...) for the next handlers.
+		SetContext(ctx context.Context) Context
+
 		// GetVar return the url var parameters. An empty string for missing case.
 		GetVar(key string) (val string)
 
+		// GetRoute return the matched route, nil if none matched.
+		// The route Path hold the full route template, prefix included.
+		GetRoute() *Route
+
 		// GetQueries return the queries into a fasthttp.Args object.
 		GetQuery() *fasthttp.Args
 
+		// GetPeerCredentials return the credentials of the peer process of
+		// an unix socket connection. False is returned for the other
+		// connections, or if the platform doesn't support it (linux only).
+		GetPeerCredentials() (PeerCredentials, bool)
+
 		// GetQuery fetch the query object key
 		// GetQuery(key string) (val string, ok bool)
 	}
@@ -70,8 +88,11 @@ type (
 	// It hold the data used by the request
 	icontext struct {
 		*fasthttp.RequestCtx
-		slog *slog.Logger
-		ctx  context.Context //nolint:containedctx
+		slog    *slog.Logger
+		loggers *loggers
+		ctx     context.Context //nolint:containedctx
+		codecs  codecs
+		debug   bool
 	}
 )
 
@@ -93,11 +114,23 @@ func (c *icontext) GetVar(key string) string {
 	return v
 }
 
+// GetRoute implement Context
+func (c *icontext) GetRoute() *Route {
+	r, _ := c.UserValue(_routeKey).(*Route)
+
+	return r
+}
+
 // GetQuery implement Context.
 func (c *icontext) GetQuery() *fasthttp.Args {
 	return c.QueryArgs()
 }
 
+// GetPeerCredentials implement Context.
+func (c *icontext) GetPeerCredentials() (PeerCredentials, bool) {
+	return peerCredentials(c.Conn())
+}
+
 // GetFastContext implement Context.
 func (c *icontext) GetFastContext() *fasthttp.RequestCtx {
 	return c.RequestCtx
@@ -115,17 +148,44 @@ func (c *icontext) GetStructuredLogger() *slog.Logger {
 	return c.slog
 }
 
+// GetLogger implement Context.
+func (c *icontext) GetLogger(name string) *slog.Logger {
+	if c.loggers == nil {
+		return c.slog
+	}
+
+	return c.loggers.logger(name, c.debug)
+}
+
 // GetContext implement Context
 func (c *icontext) GetContext() context.Context {
 	return c.ctx
 }
 
+// SetContext implement Context
+func (c *icontext) SetContext(ctx context.Context) Context {
+	c.ctx = ctx
+
+	return c
+}
+
 // FetchContent implement Context.
-// It load payload in the dest interface{} using the system json library.
+// It load payload in the dest interface{} using the codec matching the
+// request Content-Type, json being used by default.
 func (c *icontext) FetchContent(dest interface{}) ErrorHandled {
-	b := c.PostBody()
+	var (
+		b     = c.PostBody()
+		codec = JSONCodec()
+	)
+
+	if ctype := c.Request.Header.ContentType(); len(ctype) > 0 && len(c.codecs) > 0 {
+		var ok bool
+		if codec, ok = c.codecs.forContentType(string(ctype)); !ok {
+			return ErrUnsupportedContentType
+		}
+	}
 
-	if e := json.Unmarshal(b, &dest); e != nil {
+	if e := codec.Unmarshal(b, dest); e != nil {
 		c.slog.Error("fetching payload", slog.Any("error", e))
 
 		return errUnprocessablePayload
@@ -154,7 +214,7 @@ func (c *icontext) Validate(dest interface{}) ErrorHandled {
 // FetchAndValidateContent implemt Context.
 // It sucesively call FetchContent then Validate on the dest param
 func (c *icontext) FetchAndValidateContent(dest interface{}) ErrorHandled {
-	if e := c.FetchContent(&dest); e != nil {
+	if e := c.FetchContent(dest); e != nil {
 		return e
 	}
 
@@ -0,0 +1,222 @@
+package webfmwk
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned by ParseSchedule for malformed specs.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+type (
+	// Schedule compute the next run time of a Job.
+	Schedule interface {
+		// Next return the first run time strictly after t, the zero time if none.
+		Next(t time.Time) time.Time
+	}
+
+	// every run the job at a fixed interval.
+	every time.Duration
+
+	// cron hold the parsed cron fields as bit sets.
+	cron struct {
+		minute, hour, dom, month, dow uint64
+		// domStar and dowStar are true for unrestricted fields
+		domStar, dowStar bool
+	}
+
+	// cronField describe the bounds of a cron field.
+	cronField struct {
+		names    map[string]int
+		min, max int
+	}
+)
+
+var (
+	_cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+
+	_cronFields = [5]cronField{
+		{min: 0, max: 59},
+		{min: 0, max: 23},
+		{min: 1, max: 31},
+		{min: 1, max: 12, names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+		}},
+		// 7 is an alias of sunday
+		{min: 0, max: 7, names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		}},
+	}
+)
+
+// Every return a Schedule running every d.
+func Every(d time.Duration) Schedule { return every(d) }
+
+// Next implement Schedule.
+func (e every) Next(t time.Time) time.Time { return t.Add(time.Duration(e)) }
+
+// ParseSchedule parse the spec schedule, which is either:
+//   - a standard 5 fields cron expression (minute hour day-of-month month
+//     day-of-week), supporting `*`, lists, ranges, steps and the month and
+//     day names, like "*/15 9-18 * * mon-fri"
+//   - a descriptor: @yearly, @monthly, @weekly, @daily, @hourly
+//   - an interval: "@every 30s"
+//
+// The cron expressions are evaluated in the time location of the Next argument.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		i, e := time.ParseDuration(strings.TrimSpace(d))
+		if e != nil || i <= 0 {
+			return nil, fmt.Errorf("%w %q: invalid interval", ErrInvalidSchedule, spec)
+		}
+
+		return Every(i), nil
+	}
+
+	if expr, ok := _cronDescriptors[spec]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != len(_cronFields) {
+		return nil, fmt.Errorf("%w %q: expected %d fields", ErrInvalidSchedule, spec, len(_cronFields))
+	}
+
+	var (
+		c    cron
+		bits = [5]*uint64{&c.minute, &c.hour, &c.dom, &c.month, &c.dow}
+	)
+
+	for i := range fields {
+		b, e := _cronFields[i].parse(strings.ToLower(fields[i]))
+		if e != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidSchedule, spec, e)
+		}
+
+		*bits[i] = b
+	}
+
+	// sunday is both 0 and 7
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+
+	c.domStar, c.dowStar = strings.HasPrefix(fields[2], "*"), strings.HasPrefix(fields[4], "*")
+
+	return c, nil
+}
+
+// parse parse the comma separated list of the expr field.
+func (f cronField) parse(expr string) (uint64, error) {
+	var ret uint64
+
+	for _, part := range strings.Split(expr, ",") {
+		var (
+			rng, stepStr, hasStep = strings.Cut(part, "/")
+			lo, hi                = f.min, f.max
+			step                  = 1
+			e                     error
+		)
+
+		if hasStep {
+			if step, e = strconv.Atoi(stepStr); e != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+		}
+
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+
+			if lo, e = f.value(from); e != nil {
+				return 0, e
+			}
+
+			hi = lo
+
+			switch {
+			case isRange:
+				if hi, e = f.value(to); e != nil {
+					return 0, e
+				}
+			case hasStep:
+				hi = f.max
+			}
+
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			ret |= 1 << uint(v)
+		}
+	}
+
+	return ret, nil
+}
+
+// value parse a single value of the field.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[s]; ok {
+		return v, nil
+	}
+
+	v, e := strconv.Atoi(s)
+	if e != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+
+	return v, nil
+}
+
+// Next implement Schedule.
+func (c cron) Next(t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, t.Location())
+
+	// give up after 5 years, for the never matching dates like Feb 30
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchDay apply the cron rule: if both the day of month and the day of week
+// are restricted, matching either is enough.
+func (c cron) matchDay(t time.Time) bool {
+	var (
+		dom = c.dom&(1<<uint(t.Day())) != 0
+		dow = c.dow&(1<<uint(t.Weekday())) != 0
+	)
+
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
@@ -0,0 +1,52 @@
+package webfmwk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// a wednesday
+	from := time.Date(2024, time.January, 10, 10, 7, 30, 0, time.UTC)
+
+	tests := map[string]struct {
+		spec string
+		want time.Time
+	}{
+		"every minute":   {"* * * * *", time.Date(2024, 1, 10, 10, 8, 0, 0, time.UTC)},
+		"step":           {"*/15 * * * *", time.Date(2024, 1, 10, 10, 15, 0, 0, time.UTC)},
+		"list and range": {"0,30 9-11 * * *", time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)},
+		"next day":       {"0 9 * * *", time.Date(2024, 1, 11, 9, 0, 0, 0, time.UTC)},
+		"weekday names":  {"0 8 * * MON-fri", time.Date(2024, 1, 11, 8, 0, 0, 0, time.UTC)},
+		"sunday as 7":    {"0 0 * * 7", time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		"month name":     {"0 0 1 mar *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		"leap day":       {"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		"dom or dow":     {"0 0 20 * 1", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		"range step":     {"10-40/10 10 * * *", time.Date(2024, 1, 10, 10, 10, 0, 0, time.UTC)},
+		"daily":          {"@daily", time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		"hourly":         {"@hourly", time.Date(2024, 1, 10, 11, 0, 0, 0, time.UTC)},
+		"every":          {"@every 90s", from.Add(90 * time.Second)},
+		"never":          {"0 0 30 2 *", time.Time{}},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			sched, e := ParseSchedule(test.spec)
+			require.Nil(t, e)
+			assert.Equal(t, test.want, sched.Next(from))
+		})
+	}
+
+	for _, spec := range []string{
+		"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "*/0 * * * *", "5-1 * * * *", "@every", "@every -1s", "@every nope", "@often",
+	} {
+		_, e := ParseSchedule(spec)
+		assert.ErrorIs(t, e, ErrInvalidSchedule, spec)
+	}
+}
@@ -0,0 +1,269 @@
+package webfmwk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	_schemeHTTP  = "http"
+	_schemeHTTPS = "https"
+	_schemeUnix  = "unix"
+)
+
+type (
+	// Endpoint hold the parsed form of an Address Addr field, which is either:
+	//   - a bare host:port, like ":8080" or "127.0.0.1:8080"
+	//   - an url like "http://0.0.0.0:8080", "https://[::]:443?h2=1" or
+	//     "unix:///run/app.sock?mode=0660"
+	//
+	// The url form accept the following query options:
+	//   - network: tcp, tcp4 or tcp6, to force the ip stack
+	//   - h2: enable the http2 support of an https address
+	//   - keepalive: the tcp keep alive period ("30s"), negative or "off" to disable it
+	//   - nodelay: set TCP_NODELAY on the connections, default to true
+	//   - reuseport: set SO_REUSEPORT on the socket
+	//   - mode: the unix socket file permissions, in octal ("0660")
+	//   - owner, group: the unix socket file owner and group, names or ids
+	Endpoint struct {
+		// Scheme hold the url scheme, empty for the bare form.
+		Scheme string
+
+		// Network hold the net.Listen network: tcp, tcp4, tcp6 or unix.
+		Network string
+
+		// Addr hold the host:port to bind, or the unix socket path.
+		Addr string
+
+		// Owner hold the unix socket owner name or uid, empty if unset.
+		Owner string
+
+		// Group hold the unix socket group name or gid, empty if unset.
+		Group string
+
+		// KeepAlive hold the tcp keep alive period, 0 for the default and
+		// negative if disabled.
+		KeepAlive time.Duration
+
+		// Mode hold the unix socket permissions, 0 if unset.
+		Mode os.FileMode
+
+		// HTTP2 is true if the http2 support is requested.
+		HTTP2 bool
+
+		// NoDelay is true if TCP_NODELAY is set on the connections.
+		NoDelay bool
+
+		// ReusePort is true if SO_REUSEPORT is set on the socket.
+		ReusePort bool
+	}
+
+	// tcpListener apply the connection level socket options.
+	tcpListener struct {
+		*net.TCPListener
+		noDelay bool
+	}
+)
+
+// ParseEndpoint parse the addr Address Addr field. The returned errors wrap
+// ErrInvalidAddress.
+func ParseEndpoint(addr string) (Endpoint, error) {
+	ep := Endpoint{NoDelay: true}
+
+	switch {
+	case addr == "":
+		return ep, fmt.Errorf("%w: missing address", ErrInvalidAddress)
+
+	case strings.HasPrefix(addr, _unixSocketPrefix):
+		return ep, ep.parseUnix(addr)
+
+	case !strings.Contains(addr, "://"):
+		// bare host:port form, tcp4 unless an ipv6 host is used
+		if e := ep.parseHost(addr, "tcp4"); e != nil {
+			return ep, fmt.Errorf("%w %q: %w", ErrInvalidAddress, addr, e)
+		}
+
+		return ep, nil
+	}
+
+	u, e := url.Parse(addr)
+	if e != nil {
+		return ep, fmt.Errorf("%w %q: %w", ErrInvalidAddress, addr, e)
+	}
+
+	if ep.Scheme = u.Scheme; ep.Scheme != _schemeHTTP && ep.Scheme != _schemeHTTPS {
+		return ep, fmt.Errorf("%w %q: unknown scheme %q", ErrInvalidAddress, addr, u.Scheme)
+	}
+
+	if u.Path != "" && u.Path != "/" {
+		return ep, fmt.Errorf("%w %q: unexpected path %q", ErrInvalidAddress, addr, u.Path)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(strings.Trim(u.Host, "[]"), Tern(ep.Scheme == _schemeHTTPS,
+			func() string { return "443" }, func() string { return "80" }))
+	}
+
+	if e := ep.parseHost(host, "tcp"); e != nil {
+		return ep, fmt.Errorf("%w %q: %w", ErrInvalidAddress, addr, e)
+	}
+
+	if e := ep.parseQuery(u.Query()); e != nil {
+		return ep, fmt.Errorf("%w %q: %w", ErrInvalidAddress, addr, e)
+	}
+
+	if ep.HTTP2 && ep.Scheme != _schemeHTTPS {
+		return ep, fmt.Errorf("%w %q: h2 require the https scheme", ErrInvalidAddress, addr)
+	}
+
+	return ep, nil
+}
+
+// parseUnix parse the unix:// addr form.
+func (ep *Endpoint) parseUnix(addr string) error {
+	path, query, _ := strings.Cut(strings.TrimPrefix(addr, _unixSocketPrefix), "?")
+
+	ep.Scheme, ep.Network, ep.Addr = _schemeUnix, _schemeUnix, path
+
+	if path == "" {
+		return fmt.Errorf("%w %q: missing unix socket path", ErrInvalidAddress, addr)
+	}
+
+	q, e := url.ParseQuery(query)
+	if e != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidAddress, addr, e)
+	}
+
+	for k := range q {
+		switch k {
+		case "mode", "owner", "group":
+			if q.Get(k) == "" {
+				return fmt.Errorf("%w %q: empty %s option", ErrInvalidAddress, addr, k)
+			}
+		default:
+			return fmt.Errorf("%w %q: unknown unix socket option %q", ErrInvalidAddress, addr, k)
+		}
+	}
+
+	ep.Owner, ep.Group = q.Get("owner"), q.Get("group")
+
+	if m := q.Get("mode"); m != "" {
+		mode, e := strconv.ParseUint(m, 8, 32)
+		if e != nil || mode > 0o777 {
+			return fmt.Errorf("%w %q: invalid mode %q", ErrInvalidAddress, addr, m)
+		}
+
+		ep.Mode = os.FileMode(mode)
+	}
+
+	return nil
+}
+
+// parseHost validate the hostport value and set the network, defaulting
+// to def for the unspecified host.
+func (ep *Endpoint) parseHost(hostport, def string) error {
+	host, port, e := net.SplitHostPort(hostport)
+	if e != nil {
+		return e
+	}
+
+	if _, e := strconv.ParseUint(port, 10, 16); e != nil {
+		return fmt.Errorf("invalid port %q", port)
+	}
+
+	ep.Addr, ep.Network = hostport, "tcp"
+
+	switch ip := net.ParseIP(host); {
+	case host == "":
+		ep.Network = def
+	case ip != nil && ip.To4() != nil:
+		ep.Network = "tcp4"
+	case ip == nil && strings.ContainsAny(host, "[]%/ "):
+		return fmt.Errorf("invalid host %q", host)
+	}
+
+	return nil
+}
+
+// parseQuery apply the q url query options.
+func (ep *Endpoint) parseQuery(q url.Values) error {
+	for k := range q {
+		var (
+			v = q.Get(k)
+			e error
+		)
+
+		switch k {
+		case "network":
+			if v != "tcp" && v != "tcp4" && v != "tcp6" {
+				return fmt.Errorf("invalid network %q", v)
+			}
+
+			ep.Network = v
+		case "h2":
+			ep.HTTP2, e = strconv.ParseBool(v)
+		case "nodelay":
+			ep.NoDelay, e = strconv.ParseBool(v)
+		case "reuseport":
+			ep.ReusePort, e = strconv.ParseBool(v)
+		case "keepalive":
+			if v == "off" {
+				ep.KeepAlive = -1
+			} else {
+				ep.KeepAlive, e = time.ParseDuration(v)
+			}
+		case "mode", "owner", "group":
+			return fmt.Errorf("the %s option require the unix scheme", k)
+		default:
+			return fmt.Errorf("unknown option %q", k)
+		}
+
+		if e != nil {
+			return fmt.Errorf("invalid %s option %q", k, v)
+		}
+	}
+
+	return nil
+}
+
+// IsUnix return true for the unix socket endpoints.
+func (ep Endpoint) IsUnix() bool { return ep.Network == _schemeUnix }
+
+// Listen bind the endpoint, applying its socket options.
+func (ep Endpoint) Listen(ctx context.Context) (net.Listener, error) {
+	lc := net.ListenConfig{KeepAlive: ep.KeepAlive}
+
+	if ep.ReusePort {
+		lc.Control = reusePort
+	}
+
+	nl, e := lc.Listen(ctx, ep.Network, ep.Addr)
+	if e != nil {
+		return nil, e
+	}
+
+	if tl, ok := nl.(*net.TCPListener); ok && !ep.NoDelay {
+		return &tcpListener{TCPListener: tl, noDelay: ep.NoDelay}, nil
+	}
+
+	return nl, nil
+}
+
+// Accept implement net.Listener.
+func (tl *tcpListener) Accept() (net.Conn, error) {
+	c, e := tl.AcceptTCP()
+	if e != nil {
+		return nil, e
+	}
+
+	_ = c.SetNoDelay(tl.noDelay)
+
+	return c, nil
+}
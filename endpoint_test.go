@@ -0,0 +1,165 @@
+package webfmwk
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := map[string]struct {
+		addr string
+		err  string
+		want Endpoint
+	}{
+		"bare": {
+			addr: ":8080",
+			want: Endpoint{Network: "tcp4", Addr: ":8080", NoDelay: true},
+		},
+		"bare ipv6": {
+			addr: "[::1]:8080",
+			want: Endpoint{Network: "tcp", Addr: "[::1]:8080", NoDelay: true},
+		},
+		"http": {
+			addr: "http://0.0.0.0:8080",
+			want: Endpoint{Scheme: "http", Network: "tcp4", Addr: "0.0.0.0:8080", NoDelay: true},
+		},
+		"http default port": {
+			addr: "http://localhost",
+			want: Endpoint{Scheme: "http", Network: "tcp", Addr: "localhost:80", NoDelay: true},
+		},
+		"https dual stack": {
+			addr: "https://[::]:443?h2=1",
+			want: Endpoint{Scheme: "https", Network: "tcp", Addr: "[::]:443", HTTP2: true, NoDelay: true},
+		},
+		"https default port": {
+			addr: "https://[::1]",
+			want: Endpoint{Scheme: "https", Network: "tcp", Addr: "[::1]:443", NoDelay: true},
+		},
+		"socket options": {
+			addr: "http://:80?network=tcp6&keepalive=30s&nodelay=0&reuseport=true",
+			want: Endpoint{
+				Scheme: "http", Network: "tcp6", Addr: ":80",
+				KeepAlive: 30 * time.Second, ReusePort: true,
+			},
+		},
+		"keepalive off": {
+			addr: "http://:80?keepalive=off",
+			want: Endpoint{Scheme: "http", Network: "tcp", Addr: ":80", KeepAlive: -1, NoDelay: true},
+		},
+		"unix": {
+			addr: "unix:///run/app.sock?mode=0660",
+			want: Endpoint{Scheme: "unix", Network: "unix", Addr: "/run/app.sock", Mode: 0o660, NoDelay: true},
+		},
+		"unix owner": {
+			addr: "unix:///run/app.sock?owner=www-data&group=1000",
+			want: Endpoint{
+				Scheme: "unix", Network: "unix", Addr: "/run/app.sock",
+				Owner: "www-data", Group: "1000", NoDelay: true,
+			},
+		},
+		"unix empty owner": {addr: "unix:///app.sock?owner=", err: "empty owner option"},
+		"owner over tcp":   {addr: "http://:80?owner=root", err: "the owner option require the unix scheme"},
+		"empty":            {addr: "", err: "missing address"},
+		"missing port":     {addr: "localhost", err: "missing port"},
+		"invalid port":     {addr: ":http", err: `invalid port "http"`},
+		"port overflow":    {addr: "http://:65536", err: `invalid port "65536"`},
+		"unknown scheme":   {addr: "ftp://:21", err: `unknown scheme "ftp"`},
+		"path":             {addr: "http://:80/api", err: `unexpected path "/api"`},
+		"unknown option":   {addr: "http://:80?foo=1", err: `unknown option "foo"`},
+		"invalid option":   {addr: "http://:80?reuseport=maybe", err: `invalid reuseport option "maybe"`},
+		"h2 over http":     {addr: "http://:80?h2=1", err: "h2 require the https scheme"},
+		"mode over tcp":    {addr: "http://:80?mode=0660", err: "the mode option require the unix scheme"},
+		"network":          {addr: "http://:80?network=udp", err: `invalid network "udp"`},
+		"unix no path":     {addr: "unix://", err: "missing unix socket path"},
+		"unix bad mode":    {addr: "unix:///app.sock?mode=0999", err: `invalid mode "0999"`},
+		"unix option":      {addr: "unix:///app.sock?h2=1", err: `unknown unix socket option "h2"`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ep, e := ParseEndpoint(test.addr)
+
+			if test.err != "" {
+				require.ErrorIs(t, e, ErrInvalidAddress)
+				assert.Contains(t, e.Error(), test.err)
+
+				return
+			}
+
+			require.Nil(t, e)
+			assert.Equal(t, test.want, ep)
+		})
+	}
+}
+
+func TestEndpointListen(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	s.GET("/ping", func(c Context) error { return c.JSONOk(_pong) })
+
+	defer func() { require.Nil(t, s.ShutdownAndWait()) }()
+
+	var (
+		v6     = "http://[::1]:0?nodelay=0&keepalive=off"
+		reuse  = "http://127.0.0.1:0?reuseport=1"
+		sock   = filepath.Join(t.TempDir(), "app.sock")
+		report = s.Reconfigure(Addresses{{Addr: v6}, {Addr: reuse}, {Addr: "unix://" + sock + "?mode=0600"}})
+	)
+
+	if e := report.Err(); e != nil {
+		if ln, le := net.Listen("tcp6", "[::1]:0"); le != nil {
+			t.Skip("no ipv6 support:", le)
+		} else {
+			ln.Close()
+		}
+	}
+
+	require.Nil(t, report.Err())
+	require.Len(t, s.listeners.snapshot(), 3)
+
+	for _, ln := range s.listeners.snapshot() {
+		if ln.Unix {
+			continue
+		}
+
+		resp, e := http.Get("http://" + ln.raw.Addr().String() + "/ping") //nolint:noctx
+		require.Nil(t, e)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+
+	resp, e := client.Get("http://unix/ping") //nolint:noctx
+	require.Nil(t, e)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	t.Log("the reuseport socket can be bound twice")
+	{
+		for _, ln := range s.listeners.snapshot() {
+			if ln.Addr != reuse {
+				continue
+			}
+
+			ep, e := ParseEndpoint("http://" + ln.raw.Addr().String() + "?reuseport=1")
+			require.Nil(t, e)
+
+			nl, e := ep.Listen(context.Background())
+			require.Nil(t, e)
+			nl.Close()
+		}
+	}
+}
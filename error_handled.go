@@ -61,15 +61,37 @@ type (
 // HandleError test if the error argument implement the ErrorHandled interface
 // to return a matching response. Otherwise, a 500/internal error is generated
 // from the error arguent.
+// If the server use the problem details mode (WithProblemDetails), the
+// response is an application/problem+json one.
 func HandleError(ctx Context, e error) {
-	var eh ErrorHandled
-	if errors.As(e, &eh) {
+	var (
+		eh      ErrorHandled
+		p       Problem
+		problem = isProblemMode(ctx)
+	)
+
+	switch {
+	case errors.As(e, &eh) && problem:
+		_ = ctx.Problem(toProblem(ctx, eh.GetOPCode(), eh.GetContent()))
+	case errors.As(e, &eh):
 		_ = ctx.JSON(eh.GetOPCode(), eh.GetContent())
+	case errors.As(e, &p) && problem:
+		_ = ctx.Problem(toProblem(ctx, problemStatus(p), p))
+	case errors.As(e, &p):
+		_ = ctx.JSON(problemStatus(p), p)
+	case problem:
+		_ = ctx.Problem(toProblem(ctx, http.StatusInternalServerError, NewErrorFromError(e)))
+	default:
+		_ = ctx.JSONInternalError(NewErrorFromError(e))
+	}
+}
 
-		return
+func problemStatus(p Problem) int {
+	if p.Status == 0 {
+		return http.StatusInternalServerError
 	}
 
-	_ = ctx.JSONInternalError(NewErrorFromError(e))
+	return p.Status
 }
 
 // NewResponse generate a new Response struct.
@@ -148,9 +170,13 @@ func factory(op int, content interface{}) handledError {
 	}
 
 	// append status code is possible
-	if e, ok := content.(Error); ok {
+	switch e := content.(type) {
+	case Error:
 		e.SetStatusCode(op)
 		ret.content = e
+	case Problem:
+		e.Status = op
+		ret.content = e
 	}
 
 	return ret
@@ -206,6 +232,11 @@ func NewUnprocessable(content interface{}) ErrorHandled {
 	return factory(http.StatusUnprocessableEntity, content)
 }
 
+// NewTooManyRequests produce an ErrorHandled with the status code 429.
+func NewTooManyRequests(content interface{}) ErrorHandled {
+	return factory(http.StatusTooManyRequests, content)
+}
+
 // NewInternal produce an ErrorHandled with the status code 500.
 func NewInternal(content interface{}) ErrorHandled {
 	return factory(http.StatusInternalServerError, content)
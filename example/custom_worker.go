@@ -1,28 +1,59 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/burgesQ/webfmwk/v6"
-	"github.com/burgesQ/webfmwk/v6/log"
 )
 
 func customWorker() *webfmwk.Server {
-	var (
-		s  = webfmwk.InitServer()
-		wl = s.GetLauncher()
-	)
+	s, err := webfmwk.InitServer()
+	if err != nil {
+		slog.Error("init server", "error", err)
+		os.Exit(1)
+	}
 
 	// register /test
 	s.GET("/test", func(c webfmwk.Context) error {
 		return c.JSONOk("ok")
 	})
 
-	// register extra eorker
-	wl.Start("custom worker", func() error {
-		time.Sleep(10 * time.Second)
-		log.Debugf("done")
+	// register extra worker, run once
+	if err := s.StartWorker("custom worker", func(ctx context.Context) error {
+		select {
+		case <-time.After(10 * time.Second):
+			s.GetStructuredLogger().Debug("done")
+		case <-ctx.Done():
+		}
+
 		return nil
+	}, webfmwk.WorkerOneShot()); err != nil {
+		slog.Error("starting custom worker", "error", err)
+	}
+
+	// register a worker restarted on failure, and expose the workers status
+	if err := s.StartWorker("ticker", func(ctx context.Context) error {
+		t := time.NewTicker(time.Second)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-t.C:
+				s.GetStructuredLogger().Debug("tick")
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}, webfmwk.WorkerRestart(time.Second, time.Minute, 0)); err != nil {
+		slog.Error("starting ticker worker", "error", err)
+	}
+
+	s.GET("/workers", func(c webfmwk.Context) error {
+		return c.JSONOk(s.Workers())
 	})
+
 	return s
 }
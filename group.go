@@ -0,0 +1,98 @@
+package webfmwk
+
+// Group hold a set of routes sharing a common prefix and a common
+// handler chain. Groups may be nested, a nested group inherit the prefix,
+// the handlers and the limits (see WithLimits) of its parent.
+//
+//	s, _ := webfmwk.InitServer(webfmwk.SetPrefix("/api"))
+//
+//	v1 := s.Group("/v1")
+//	v1.GET("/users", listUsers)
+//
+//	admin := s.Group("/v2", auth.Handler).Group("/admin", audit.Handler)
+//	admin.DELETE("/users/{id}", deleteUser)
+type Group struct {
+	s        *Server
+	prefix   string
+	handlers []Handler
+	limits   RouteLimits
+}
+
+// Group create a new routes group. The group prefix is appended to the server
+// prefix and the mw Handlers are only applied to the group routes.
+// The mw Handlers are chained the same way the WithHandlers ones are.
+func (s *Server) Group(prefix string, mw ...Handler) *Group {
+	return &Group{
+		s:        s,
+		prefix:   s.meta.prefix + prefix,
+		handlers: mw,
+	}
+}
+
+// Group create a nested routes group. The parent handlers wrap the mw ones,
+// meaning they are executed before them.
+func (g *Group) Group(prefix string, mw ...Handler) *Group {
+	handlers := make([]Handler, 0, len(mw)+len(g.handlers))
+	handlers = append(handlers, mw...)
+	handlers = append(handlers, g.handlers...)
+
+	return &Group{
+		s:        g.s,
+		prefix:   g.prefix + prefix,
+		handlers: handlers,
+		limits:   g.limits,
+	}
+}
+
+// GetPrefix return the full group prefix, server prefix included.
+func (g *Group) GetPrefix() string { return g.prefix }
+
+// AddRoutes add the endpoints to the group.
+func (g *Group) AddRoutes(r ...Route) {
+	for i := range r {
+		route := r[i]
+		route.Limits = route.Limits.inherit(g.limits)
+
+		if len(g.handlers) > 0 {
+			var mdlws []Handler
+			if route.Middlewares != nil {
+				mdlws = append(mdlws, *route.Middlewares...)
+			}
+
+			mdlws = append(mdlws, g.handlers...)
+			route.Middlewares = &mdlws
+		}
+
+		g.s.meta.routes[g.prefix] = append(g.s.meta.routes[g.prefix], route)
+	}
+}
+
+// GET expose a handler to the http verb GET.
+func (g *Group) GET(path string, handler HandlerFunc) {
+	g.AddRoutes(Route{Path: path, Verbe: GET, Handler: handler})
+}
+
+// DELETE expose a handler to the http verb DELETE.
+func (g *Group) DELETE(path string, handler HandlerFunc) {
+	g.AddRoutes(Route{Path: path, Verbe: DELETE, Handler: handler})
+}
+
+// POST expose a handler to the http verb POST.
+func (g *Group) POST(path string, handler HandlerFunc) {
+	g.AddRoutes(Route{Path: path, Verbe: POST, Handler: handler})
+}
+
+// PUT expose a handler to the http verb PUT.
+func (g *Group) PUT(path string, handler HandlerFunc) {
+	g.AddRoutes(Route{Path: path, Verbe: PUT, Handler: handler})
+}
+
+// PATCH expose a handler to the http verb PATCH.
+func (g *Group) PATCH(path string, handler HandlerFunc) {
+	g.AddRoutes(Route{Path: path, Verbe: PATCH, Handler: handler})
+}
+
+// ANY expose a handler to all the http verbs.
+func (g *Group) ANY(path string, handler HandlerFunc) {
+	g.AddRoutes(Route{Path: path, Verbe: ANY, Handler: handler})
+}
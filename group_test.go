@@ -0,0 +1,83 @@
+package webfmwk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func headerHandler(k, v string) Handler {
+	return func(next HandlerFunc) HandlerFunc {
+		return HandlerFunc(func(c Context) error {
+			c.SetHeader(k, v)
+
+			return next(c)
+		})
+	}
+}
+
+func requestRouter(t *testing.T, s *Server, method, uri string) *fasthttp.RequestCtx {
+	t.Helper()
+
+	fc := &fasthttp.RequestCtx{}
+	fc.Request.Header.SetMethod(method)
+	fc.Request.SetRequestURI(uri)
+
+	s.GetRouter().Handler(fc)
+
+	return fc
+}
+
+func TestGroup(t *testing.T) {
+	s, e := InitServer(SetPrefix("/api"))
+	require.Nil(t, e)
+
+	var (
+		v1    = s.Group("/v1", headerHandler("X-Version", "1"))
+		v2    = s.Group("/v2", headerHandler("X-Version", "2"))
+		admin = v2.Group("/admin", func(next HandlerFunc) HandlerFunc {
+			return HandlerFunc(func(c Context) error {
+				if len(c.GetFastContext().Request.Header.Peek("Authorization")) == 0 {
+					return NewUnauthorized(NewError("missing credentials"))
+				}
+
+				return next(c)
+			})
+		}, headerHandler("X-Admin", "true"))
+	)
+
+	s.GET("/root", _emptyController)
+	v1.GET("/users", _emptyController)
+	v2.POST("/users", _emptyController)
+	admin.DELETE("/users/{id}", _emptyController)
+
+	t.Log("ensure routes are prefixed")
+	{
+		all := s.GetRouter().List()
+		assert.ElementsMatch(t, []string{"/api/root", "/api/v1/users"}, all[GET])
+		assert.Equal(t, []string{"/api/v2/users"}, all[POST])
+		assert.Equal(t, []string{"/api/v2/admin/users/{id}"}, all[DELETE])
+		assert.Equal(t, "/api/v2/admin", admin.GetPrefix())
+	}
+
+	t.Log("ensure handlers are scoped")
+	{
+		fc := requestRouter(t, s, GET, "/api/root")
+		assert.Empty(t, fc.Response.Header.Peek("X-Version"))
+
+		fc = requestRouter(t, s, GET, "/api/v1/users")
+		assert.Equal(t, "1", string(fc.Response.Header.Peek("X-Version")))
+		assert.Empty(t, fc.Response.Header.Peek("X-Admin"))
+	}
+
+	t.Log("ensure nested handlers are inherited")
+	{
+		fc := requestRouter(t, s, DELETE, "/api/v2/admin/users/42")
+		assert.Equal(t, http.StatusUnauthorized, fc.Response.StatusCode())
+		assert.Equal(t, "2", string(fc.Response.Header.Peek("X-Version")))
+		assert.Equal(t, "true", string(fc.Response.Header.Peek("X-Admin")))
+	}
+}
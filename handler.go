@@ -1,8 +1,8 @@
 package webfmwk
 
 import (
-	"bytes"
 	"log/slog"
+	"net/http"
 
 	"github.com/segmentio/encoding/json"
 	"github.com/valyala/fasthttp"
@@ -13,9 +13,10 @@ var (
 	ErrMissingContentType = NewNotAcceptable(NewError("Missing Content-Type header"))
 
 	// ErrNotJSON is returned when the content type isn't json
+	//
+	// Deprecated: the payloads are decoded using the registered codecs,
+	// ErrUnsupportedContentType is returned instead.
 	ErrNotJSON = NewNotAcceptable(NewError("Content-Type is not application/json"))
-
-	_prefixContentType = []byte("application/json")
 )
 
 //
@@ -49,7 +50,8 @@ func handleHandlerError(next HandlerFunc) HandlerFunc {
 	})
 }
 
-func contentIsJSON(next HandlerFunc) HandlerFunc {
+// contentIsSupported ensure that a codec is registered for the payload Content-Type.
+func (s *Server) contentIsSupported(next HandlerFunc) HandlerFunc {
 	return HandlerFunc(func(c Context) error {
 		var (
 			fc = c.GetFastContext()
@@ -59,8 +61,8 @@ func contentIsJSON(next HandlerFunc) HandlerFunc {
 		if string(m) == POST || string(m) == PUT || string(m) == PATCH {
 			if ctype := fc.Request.Header.Peek("Content-Type"); len(ctype) == 0 {
 				return ErrMissingContentType
-			} else if !bytes.HasPrefix(ctype, _prefixContentType) {
-				return ErrNotJSON
+			} else if _, ok := s.meta.codecs.forContentType(string(ctype)); !ok {
+				return ErrUnsupportedContentType
 			}
 		}
 
@@ -76,6 +78,10 @@ func handleNotFound(c Context) error {
 		slog.String("method", string(fc.Method())),
 		slog.String("uri", string(fc.RequestURI()))))
 
+	if isProblemMode(c) {
+		return c.Problem(toProblem(c, http.StatusNotFound, "no such resource"))
+	}
+
 	return c.JSONNotFound(json.RawMessage(`{"status":404,"message":"not found"}`))
 }
 
@@ -87,5 +93,10 @@ func handleNotAllowed(c Context) error {
 		slog.String("method", string(fc.Method())),
 		slog.String("uri", string(fc.RequestURI()))))
 
+	if isProblemMode(c) {
+		return c.Problem(toProblem(c, http.StatusMethodNotAllowed,
+			string(fc.Method())+" is not allowed on this resource"))
+	}
+
 	return c.JSONMethodNotAllowed(json.RawMessage(`{"status":405,"message":"method not allowed"}`))
 }
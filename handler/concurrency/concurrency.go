@@ -0,0 +1,52 @@
+// Package concurrency implement an handler bounding the number of in-flight
+// requests, shedding the excess load instead of degrading every client.
+//
+// The handler may be registered server wide, per group or per route:
+//
+//	global := concurrency.NewLimiter(concurrency.Config{Limit: 512, Queue: 128, Wait: time.Second})
+//
+//	s, _ := webfmwk.InitServer(webfmwk.WithHandlers(concurrency.NewHandler(global)))
+//	s.Group("/reports", concurrency.NewHandler(concurrency.NewLimiter(concurrency.Config{
+//		Limit: 8, Adaptive: &concurrency.Adaptive{Latency: 200 * time.Millisecond}})))
+//
+// Requests exceeding the limit wait in the bounded queue, if any. The
+// rejected ones are answered a 503 with the Retry-After header. They are
+// counted by Stats.Rejected and only logged at the debug level, so an
+// overload doesn't flood the logs.
+//
+// The connections themselves are bounded by the webfmwk.SetConcurrency,
+// webfmwk.SetMaxConnsPerIP and webfmwk.SetMaxRequestsPerConn options.
+package concurrency
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6"
+)
+
+// HeaderRetryAfter is set on the rejected requests.
+const HeaderRetryAfter = "Retry-After"
+
+// NewHandler return a webfmwk.Handler bounding the in-flight requests via
+// the l limiter. A limiter may be shared by several handlers.
+func NewHandler(l *Limiter) webfmwk.Handler {
+	return func(next webfmwk.HandlerFunc) webfmwk.HandlerFunc {
+		return webfmwk.HandlerFunc(func(c webfmwk.Context) error {
+			if e := l.Acquire(c.GetContext()); e != nil {
+				c.GetStructuredLogger().Debug("request shed", slog.Any("error", e))
+				c.SetHeader(HeaderRetryAfter, strconv.Itoa(int(math.Ceil(l.cfg.RetryAfter.Seconds()))))
+
+				return webfmwk.NewServiceUnavailable(webfmwk.NewError("server overloaded"))
+			}
+
+			start := time.Now()
+
+			defer func() { l.Release(time.Since(start)) }()
+
+			return next(c)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package concurrency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestLimiter(t *testing.T) {
+	var (
+		ctx = context.Background()
+		l   = NewLimiter(Config{Limit: 2, Queue: 1, Wait: time.Second})
+	)
+
+	require.Nil(t, l.Acquire(ctx))
+	require.Nil(t, l.Acquire(ctx))
+
+	queued := make(chan error, 1)
+
+	go func() { queued <- l.Acquire(ctx) }()
+
+	require.Eventually(t, func() bool { return l.Stats().Queued == 1 }, time.Second, time.Millisecond)
+
+	t.Log("the queue is bounded")
+	require.ErrorIs(t, l.Acquire(ctx), ErrLimitExceeded)
+
+	t.Log("a released slot is handed to the queued request")
+	l.Release(0)
+	require.Nil(t, <-queued)
+	assert.Equal(t, Stats{Limit: 2, InFlight: 2, Rejected: 1}, l.Stats())
+
+	t.Log("the queue wait is bounded")
+	{
+		l := NewLimiter(Config{Limit: 1, Queue: 1, Wait: 10 * time.Millisecond})
+		require.Nil(t, l.Acquire(ctx))
+		require.ErrorIs(t, l.Acquire(ctx), ErrQueueTimeout)
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		require.ErrorIs(t, l.Acquire(cctx), context.Canceled)
+		assert.Equal(t, Stats{Limit: 1, InFlight: 1, Rejected: 2}, l.Stats())
+	}
+}
+
+func TestAdaptive(t *testing.T) {
+	var (
+		ctx = context.Background()
+		l   = NewLimiter(Config{Limit: 10, Adaptive: &Adaptive{Latency: time.Second, Min: 2, Decrease: 0.5}})
+	)
+
+	for _, want := range []int{5, 2, 2} {
+		require.Nil(t, l.Acquire(ctx))
+		l.Release(2 * time.Second)
+		assert.Equal(t, want, l.Stats().Limit)
+	}
+
+	t.Log("the limit grow back up to the configured one")
+
+	for i := 0; i < 100; i++ {
+		require.Nil(t, l.Acquire(ctx))
+		l.Release(time.Millisecond)
+	}
+
+	assert.Equal(t, 10, l.Stats().Limit)
+}
+
+func TestHandler(t *testing.T) {
+	var (
+		l       = NewLimiter(Config{Limit: 1, RetryAfter: 1500 * time.Millisecond})
+		started = make(chan struct{})
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+
+	s, e := webfmwk.InitServer(webfmwk.WithHandlers(NewHandler(l)))
+	require.Nil(t, e)
+
+	s.GET("/slow", func(c webfmwk.Context) error {
+		close(started)
+		<-release
+
+		return c.JSONNoContent()
+	})
+
+	var (
+		r   = s.GetRouter()
+		req = func() *fasthttp.RequestCtx {
+			fc := &fasthttp.RequestCtx{}
+			fc.Request.Header.SetMethod(http.MethodGet)
+			fc.Request.SetRequestURI("/slow")
+			r.Handler(fc)
+
+			return fc
+		}
+	)
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		assert.Equal(t, http.StatusNoContent, req().Response.StatusCode())
+	}()
+
+	<-started
+
+	fc := req()
+	assert.Equal(t, http.StatusServiceUnavailable, fc.Response.StatusCode())
+	assert.Equal(t, "2", string(fc.Response.Header.Peek(HeaderRetryAfter)))
+
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, Stats{Limit: 1, Rejected: 1}, l.Stats())
+}
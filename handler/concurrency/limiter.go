@@ -0,0 +1,200 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	_defaultRetryAfter = time.Second
+	_defaultDecrease   = 0.9
+)
+
+var (
+	// ErrLimitExceeded is returned by Acquire if the limit and the queue
+	// are full.
+	ErrLimitExceeded = errors.New("concurrency limit exceeded")
+
+	// ErrQueueTimeout is returned by Acquire if the request waited in the
+	// queue longer than Config.Wait.
+	ErrQueueTimeout = errors.New("concurrency queue wait timeout")
+)
+
+type (
+	// Config hold the limiter configuration.
+	Config struct {
+		// Adaptive, if set, adjust the limit to the observed latency.
+		Adaptive *Adaptive
+
+		// Limit hold the maximum number of in-flight requests.
+		Limit int
+
+		// Queue hold the maximum number of requests waiting for a slot.
+		// Zero reject the excess requests straight away.
+		Queue int
+
+		// Wait bound the time a request wait in the queue. Zero wait until
+		// the request context is done.
+		Wait time.Duration
+
+		// RetryAfter hold the Retry-After delay sent with the rejections.
+		// Default to one second.
+		RetryAfter time.Duration
+	}
+
+	// Adaptive hold the AIMD configuration: the limit is increased by one
+	// every limit requests served under the Latency target, and multiplied
+	// by Decrease for a request served over it. The limit stay within Min
+	// and Config.Limit.
+	Adaptive struct {
+		// Latency hold the target latency.
+		Latency time.Duration
+
+		// Min hold the minimum limit. Default to 1.
+		Min int
+
+		// Decrease hold the multiplicative decrease factor, in ]0, 1[.
+		// Default to 0.9.
+		Decrease float64
+	}
+
+	// Stats hold the limiter statistics.
+	Stats struct {
+		Limit    int    `json:"limit"`
+		InFlight int    `json:"in_flight"`
+		Queued   int    `json:"queued"`
+		Rejected uint64 `json:"rejected"`
+	}
+
+	// Limiter bound the number of concurrent requests, queueing the excess
+	// ones.
+	Limiter struct {
+		cfg      Config
+		queue    []chan struct{}
+		limit    float64
+		inFlight int
+		rejected uint64
+		mu       sync.Mutex
+	}
+)
+
+// NewLimiter return a Limiter. A Limit lower than 1 default to 1.
+func NewLimiter(cfg Config) *Limiter {
+	cfg.Limit = max(cfg.Limit, 1)
+	cfg.Queue = max(cfg.Queue, 0)
+
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = _defaultRetryAfter
+	}
+
+	if cfg.Adaptive != nil {
+		a := *cfg.Adaptive
+		a.Min = min(max(a.Min, 1), cfg.Limit)
+
+		if a.Decrease <= 0 || a.Decrease >= 1 {
+			a.Decrease = _defaultDecrease
+		}
+
+		cfg.Adaptive = &a
+	}
+
+	return &Limiter{cfg: cfg, limit: float64(cfg.Limit)}
+}
+
+// Acquire take a slot, waiting in the queue if the limit is reached.
+// ErrLimitExceeded is returned if the queue is full, ErrQueueTimeout if the
+// wait exceeded Config.Wait, and the ctx error if it's done first.
+// Release must be called once the request is served.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	l.mu.Lock()
+
+	if l.inFlight < l.current() {
+		l.inFlight++
+		l.mu.Unlock()
+
+		return nil
+	}
+
+	if len(l.queue) >= l.cfg.Queue {
+		l.rejected++
+		l.mu.Unlock()
+
+		return ErrLimitExceeded
+	}
+
+	slot := make(chan struct{})
+	l.queue = append(l.queue, slot)
+	l.mu.Unlock()
+
+	var timeout <-chan time.Time
+
+	if l.cfg.Wait > 0 {
+		t := time.NewTimer(l.cfg.Wait)
+		defer t.Stop()
+
+		timeout = t.C
+	}
+
+	select {
+	case <-slot:
+		return nil
+	case <-timeout:
+		return l.leave(slot, ErrQueueTimeout)
+	case <-ctx.Done():
+		return l.leave(slot, ctx.Err())
+	}
+}
+
+// Release free the slot taken by Acquire, elapsed being the request
+// latency fed to the adaptive limit.
+func (l *Limiter) Release(elapsed time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if a := l.cfg.Adaptive; a != nil {
+		if elapsed > a.Latency {
+			l.limit = max(l.limit*a.Decrease, float64(a.Min))
+		} else {
+			l.limit = min(l.limit+1/l.limit, float64(l.cfg.Limit))
+		}
+	}
+
+	l.inFlight--
+
+	for len(l.queue) > 0 && l.inFlight < l.current() {
+		close(l.queue[0])
+		l.queue = l.queue[1:]
+		l.inFlight++
+	}
+}
+
+// Stats return the limiter statistics.
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return Stats{Limit: l.current(), InFlight: l.inFlight, Queued: len(l.queue), Rejected: l.rejected}
+}
+
+// current return the current limit. The lock must be held.
+func (l *Limiter) current() int { return int(l.limit) }
+
+// leave remove the slot from the queue, returning err. If the slot was
+// granted meanwhile, it's kept and nil is returned.
+func (l *Limiter) leave(slot chan struct{}, err error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range l.queue {
+		if l.queue[i] == slot {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			l.rejected++
+
+			return err
+		}
+	}
+
+	return nil
+}
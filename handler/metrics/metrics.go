@@ -0,0 +1,307 @@
+// Package metrics implement an handler recording the requests and connections
+// metrics of a webfmwk server, exposed in the Prometheus text format.
+//
+//	m := metrics.New()
+//
+//	s, _ := webfmwk.InitServer(
+//		webfmwk.WithHandlers(m.Handler()),
+//		webfmwk.WithConnObservers(m),
+//		webfmwk.WithAdminAddress(webfmwk.Address{Addr: "127.0.0.1:9090"}),
+//		webfmwk.WithAdminHandlers(m.DocHandler()))
+//
+// The metrics are then only reachable from the admin listener.
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	_defPath      = "/metrics"
+	_defNamespace = "webfmwk"
+	_unmatched    = "unmatched"
+
+	// ContentType is the Prometheus text exposition format content type.
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	// DefBuckets hold the default latency buckets, in seconds.
+	DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	// DefSizeBuckets hold the default request and response size buckets, in bytes.
+	DefSizeBuckets = []float64{100, 1000, 10000, 100000, 1e6, 1e7}
+)
+
+type (
+	// Observation hold the metrics of a completed request.
+	Observation struct {
+		// Method hold the request method.
+		Method string
+
+		// Route hold the matched route template (ex: /users/{id}).
+		Route string
+
+		// Status hold the response status code.
+		Status int
+
+		// Duration hold the time spent processing the request.
+		Duration time.Duration
+
+		// RequestSize and ResponseSize hold the payloads size, in bytes.
+		RequestSize, ResponseSize int
+	}
+
+	// Recorder receive the observations of the Handler and of the listeners.
+	// Registry is the builtin implementation; implement Recorder to forward
+	// the metrics to another backend (ex: the Prometheus client).
+	Recorder interface {
+		webfmwk.ConnObserver
+
+		// RequestStarted is called when a request enter the handler.
+		RequestStarted()
+
+		// RequestDone is called once the request has been processed.
+		RequestDone(o Observation)
+	}
+
+	// Param hold the Registry settings.
+	Param struct {
+		// Path hold the value on which the metrics are exposed.
+		Path string
+
+		// Namespace prefix the metrics name.
+		Namespace string
+
+		// Buckets hold the latency histogram buckets, in seconds.
+		Buckets []float64
+
+		// SizeBuckets hold the size histograms buckets, in bytes.
+		SizeBuckets []float64
+	}
+
+	// Registry implement Recorder, holding the metrics in memory
+	// and exposing them in the Prometheus text format.
+	Registry struct {
+		requests   *family
+		duration   *family
+		inFlight   *family
+		reqSize    *family
+		respSize   *family
+		handshakes *family
+		conns      *family
+		connsTotal *family
+		families   []*family
+		p          Param
+	}
+
+	countWriter struct {
+		w io.Writer
+		n int64
+	}
+)
+
+func defParam() *Param {
+	return &Param{
+		Path:        _defPath,
+		Namespace:   _defNamespace,
+		Buckets:     DefBuckets,
+		SizeBuckets: DefSizeBuckets,
+	}
+}
+
+// Path set the metrics handler path.
+func Path(path string) func(*Param) {
+	return func(p *Param) {
+		p.Path = path
+	}
+}
+
+// Namespace set the metrics name prefix.
+func Namespace(ns string) func(*Param) {
+	return func(p *Param) {
+		p.Namespace = ns
+	}
+}
+
+// Buckets set the latency histogram buckets, in seconds.
+func Buckets(b ...float64) func(*Param) {
+	return func(p *Param) {
+		p.Buckets = b
+	}
+}
+
+// SizeBuckets set the size histograms buckets, in bytes.
+func SizeBuckets(b ...float64) func(*Param) {
+	return func(p *Param) {
+		p.SizeBuckets = b
+	}
+}
+
+// New return an initialized Registry.
+func New(opt ...func(*Param)) *Registry {
+	p := defParam()
+
+	for _, o := range opt {
+		o(p)
+	}
+
+	var (
+		ns = p.Namespace + "_"
+		r  = &Registry{
+			p: *p,
+			requests: newFamily(ns+"http_requests_total",
+				"Total number of processed requests.", _counter, nil, "method", "route", "code"),
+			duration: newFamily(ns+"http_request_duration_seconds",
+				"Requests processing time, in seconds.", _histogram, p.Buckets, "method", "route", "code"),
+			inFlight: newFamily(ns+"http_requests_in_flight",
+				"Number of requests being processed.", _gauge, nil),
+			reqSize: newFamily(ns+"http_request_size_bytes",
+				"Requests payload size, in bytes.", _histogram, p.SizeBuckets, "method", "route", "code"),
+			respSize: newFamily(ns+"http_response_size_bytes",
+				"Responses payload size, in bytes.", _histogram, p.SizeBuckets, "method", "route", "code"),
+			handshakes: newFamily(ns+"tls_handshakes_total",
+				"Total number of TLS handshakes.", _counter, nil, "name", "address", "result"),
+			conns: newFamily(ns+"connections_active",
+				"Number of open connections.", _gauge, nil, "name", "address"),
+			connsTotal: newFamily(ns+"connections_total",
+				"Total number of accepted connections.", _counter, nil, "name", "address"),
+		}
+	)
+
+	r.inFlight.add(0)
+	r.families = []*family{
+		r.requests, r.duration, r.inFlight, r.reqSize,
+		r.respSize, r.handshakes, r.conns, r.connsTotal,
+	}
+
+	return r
+}
+
+// Handler return the webfmwk.Handler feeding the registry.
+func (r *Registry) Handler() webfmwk.Handler { return NewHandler(r) }
+
+// DocHandler return a DocHandler exposing the registry.
+func (r *Registry) DocHandler() webfmwk.DocHandler {
+	return webfmwk.DocHandler{
+		Name: "metrics",
+		Path: r.p.Path,
+		H: func(c webfmwk.Context) error {
+			c.SetContentType(ContentType)
+			c.SetStatusCode(http.StatusOK)
+			_, e := r.WriteTo(c.GetFastContext())
+
+			return e
+		},
+	}
+}
+
+// WriteTo implement io.WriterTo, dumping the metrics in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	var (
+		cw  = &countWriter{w: w}
+		buf = bufio.NewWriter(cw)
+	)
+
+	for _, f := range r.families {
+		f.write(buf)
+	}
+
+	e := buf.Flush()
+
+	return cw.n, e
+}
+
+// RequestStarted implement Recorder.
+func (r *Registry) RequestStarted() { r.inFlight.add(1) }
+
+// RequestDone implement Recorder.
+func (r *Registry) RequestDone(o Observation) {
+	code := strconv.Itoa(o.Status)
+
+	r.inFlight.add(-1)
+	r.requests.add(1, o.Method, o.Route, code)
+	r.duration.observe(o.Duration.Seconds(), o.Method, o.Route, code)
+	r.reqSize.observe(float64(o.RequestSize), o.Method, o.Route, code)
+	r.respSize.observe(float64(o.ResponseSize), o.Method, o.Route, code)
+}
+
+// ConnState implement webfmwk.ConnObserver.
+func (r *Registry) ConnState(l webfmwk.Listener, state fasthttp.ConnState) {
+	switch state {
+	case fasthttp.StateNew:
+		r.connsTotal.add(1, l.Name, l.Addr)
+		r.conns.add(1, l.Name, l.Addr)
+	case fasthttp.StateClosed, fasthttp.StateHijacked:
+		r.conns.add(-1, l.Name, l.Addr)
+	case fasthttp.StateActive, fasthttp.StateIdle:
+	}
+}
+
+// TLSHandshake implement webfmwk.ConnObserver.
+func (r *Registry) TLSHandshake(l webfmwk.Listener, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	r.handshakes.add(1, l.Name, l.Addr, result)
+}
+
+// NewHandler return a webfmwk.Handler reporting the requests metrics to rec.
+// The route label hold the matched route template, not the raw URI, or
+// "unmatched" for the 404 and 405 answers.
+func NewHandler(rec Recorder) webfmwk.Handler {
+	return func(next webfmwk.HandlerFunc) webfmwk.HandlerFunc {
+		return webfmwk.HandlerFunc(func(c webfmwk.Context) error {
+			var (
+				start = time.Now()
+				fc    = c.GetFastContext()
+				route = _unmatched
+			)
+
+			rec.RequestStarted()
+
+			// reported as a 500 if next panic, the panic being propagated
+			defer func() {
+				status, p := fc.Response.StatusCode(), recover()
+				if p != nil {
+					status = http.StatusInternalServerError
+				}
+
+				if r := c.GetRoute(); r != nil {
+					route = r.Path
+				}
+
+				rec.RequestDone(Observation{
+					Method:       string(fc.Method()),
+					Route:        route,
+					Status:       status,
+					Duration:     time.Since(start),
+					RequestSize:  len(fc.Request.Body()),
+					ResponseSize: len(fc.Response.Body()),
+				})
+
+				if p != nil {
+					panic(p)
+				}
+			}()
+
+			return next(c)
+		})
+	}
+}
+
+func (cw *countWriter) Write(b []byte) (int, error) {
+	n, e := cw.w.Write(b)
+	cw.n += int64(n)
+
+	return n, e //nolint:wrapcheck
+}
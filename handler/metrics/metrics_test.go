@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/burgesQ/gommon/port"
+	"github.com/burgesQ/gommon/webtest"
+	"github.com/burgesQ/webfmwk/v6"
+	"github.com/burgesQ/webfmwk/v6/handler/recover"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRegistryFormat(t *testing.T) {
+	r := New(Namespace("test"), Buckets(0.1, 1), SizeBuckets(10))
+
+	r.RequestStarted()
+	r.RequestDone(Observation{Method: "GET", Route: `/a"b`, Status: 200, RequestSize: 20, ResponseSize: 5})
+	r.TLSHandshake(webfmwk.Listener{Name: "main", Addr: ":4242"}, errors.New("bad cert"))
+
+	var buf strings.Builder
+
+	n, e := r.WriteTo(&buf)
+	require.Nil(t, e)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE test_http_requests_total counter",
+		`test_http_requests_total{method="GET",route="/a\"b",code="200"} 1`,
+		`test_http_request_duration_seconds_bucket{method="GET",route="/a\"b",code="200",le="0.1"} 1`,
+		`test_http_request_duration_seconds_bucket{method="GET",route="/a\"b",code="200",le="+Inf"} 1`,
+		`test_http_request_duration_seconds_count{method="GET",route="/a\"b",code="200"} 1`,
+		`test_http_request_size_bytes_bucket{method="GET",route="/a\"b",code="200",le="10"} 0`,
+		`test_http_response_size_bytes_sum{method="GET",route="/a\"b",code="200"} 5`,
+		"test_http_requests_in_flight 0",
+		`test_tls_handshakes_total{name="main",address=":4242",result="failure"} 1`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var (
+		m    = New()
+		s, e = webfmwk.InitServer(webfmwk.CheckIsUp(),
+			webfmwk.WithHandlers(m.Handler(), recover.Handler),
+			webfmwk.WithConnObservers(m),
+			webfmwk.WithAdminAddress(webfmwk.Address{Addr: "127.0.0.1:0"}),
+			webfmwk.WithAdminHandlers(m.DocHandler()))
+	)
+
+	require.Nil(t, e)
+
+	t.Cleanup(func() { require.Nil(t, s.ShutdownAndWait()) })
+
+	s.GET("/users/{id}", func(c webfmwk.Context) error {
+		return c.JSONOk(c.GetVar("id"))
+	})
+	s.GET("/panic", func(webfmwk.Context) error { panic("boom") })
+
+	p, e := port.GetFree()
+	require.Nil(t, e)
+
+	var (
+		addr = fmt.Sprintf(":%d", p)
+		uri  = "http://127.0.0.1" + addr
+	)
+
+	go s.Start(addr)
+	<-s.IsReady()
+
+	webtest.RequestAndTestAPI(t, uri+"/panic", func(t *testing.T, resp *http.Response) {
+		t.Helper()
+		webtest.StatusCode(t, http.StatusInternalServerError, resp)
+	})
+
+	for _, id := range []string{"1", "2"} {
+		webtest.RequestAndTestAPI(t, uri+"/users/"+id, func(t *testing.T, resp *http.Response) {
+			t.Helper()
+			webtest.StatusCode(t, http.StatusOK, resp)
+		})
+	}
+
+	fc := &fasthttp.RequestCtx{}
+	fc.Request.Header.SetMethod(webfmwk.GET)
+	fc.Request.SetRequestURI("/metrics")
+	s.GetRouter().Handler(fc)
+
+	assert.Equal(t, http.StatusNotFound, fc.Response.StatusCode(), "not exposed publicly")
+
+	fc = &fasthttp.RequestCtx{}
+	fc.Request.Header.SetMethod(webfmwk.GET)
+	fc.Request.SetRequestURI("/metrics")
+	s.GetAdminRouter().Handler(fc)
+
+	require.Equal(t, http.StatusOK, fc.Response.StatusCode())
+	assert.Equal(t, ContentType, string(fc.Response.Header.ContentType()))
+
+	out := string(fc.Response.Body())
+	assert.Contains(t, out, `webfmwk_http_requests_total{method="GET",route="/users/{id}",code="200"} 2`+"\n")
+	assert.NotContains(t, out, `route="/users/1"`)
+	assert.Contains(t, out, `webfmwk_http_requests_total{method="GET",route="unmatched",code="404"} 1`+"\n")
+	assert.Contains(t, out, `webfmwk_http_requests_total{method="GET",route="/panic",code="500"} 1`+"\n")
+	assert.Contains(t, out, "webfmwk_http_requests_in_flight 0\n")
+	assert.Contains(t, out, `webfmwk_connections_total{name="",address="`+addr+`"}`)
+	assert.Contains(t, out, `webfmwk_connections_active{name="",address="`+addr+`"}`)
+}
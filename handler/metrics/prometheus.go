@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"bufio"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	_counter   = "counter"
+	_gauge     = "gauge"
+	_histogram = "histogram"
+
+	_labelSep = "\xff"
+)
+
+type (
+	// series hold the value(s) of a family for a set of label values.
+	series struct {
+		values  []string
+		buckets []uint64
+		value   float64
+		count   uint64
+	}
+
+	// family hold a metric and all its series.
+	family struct {
+		series  map[string]*series
+		name    string
+		help    string
+		kind    string
+		labels  []string
+		buckets []float64
+		mu      sync.Mutex
+	}
+)
+
+func newFamily(name, help, kind string, buckets []float64, labels ...string) *family {
+	return &family{
+		series:  make(map[string]*series),
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+	}
+}
+
+// with return the series of the values label values. Lock must be held.
+func (f *family) with(values ...string) *series {
+	k := strings.Join(values, _labelSep)
+
+	ser, ok := f.series[k]
+	if !ok {
+		ser = &series{values: values}
+		if f.kind == _histogram {
+			ser.buckets = make([]uint64, len(f.buckets))
+		}
+
+		f.series[k] = ser
+	}
+
+	return ser
+}
+
+// add add v to the counter or gauge series.
+func (f *family) add(v float64, values ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.with(values...).value += v
+}
+
+// observe record the v value in the histogram series.
+func (f *family) observe(v float64, values ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ser := f.with(values...)
+	ser.count++
+	ser.value += v
+
+	for i, b := range f.buckets {
+		if v <= b {
+			ser.buckets[i]++
+		}
+	}
+}
+
+// write dump the family in the Prometheus text format.
+func (f *family) write(w *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.WriteString("# HELP " + f.name + " " + f.help + "\n")
+	w.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		ser := f.series[k]
+
+		if f.kind != _histogram {
+			writeSample(w, f.name, f.labels, ser.values, "", "", ser.value)
+
+			continue
+		}
+
+		for i, b := range f.buckets {
+			writeSample(w, f.name+"_bucket", f.labels, ser.values, "le", formatFloat(b), float64(ser.buckets[i]))
+		}
+
+		writeSample(w, f.name+"_bucket", f.labels, ser.values, "le", "+Inf", float64(ser.count))
+		writeSample(w, f.name+"_sum", f.labels, ser.values, "", "", ser.value)
+		writeSample(w, f.name+"_count", f.labels, ser.values, "", "", float64(ser.count))
+	}
+}
+
+// writeSample write a sample line. The extra label is appended if not empty.
+func writeSample(w *bufio.Writer, name string, labels, values []string, extra, extraVal string, v float64) {
+	w.WriteString(name)
+
+	if len(labels) > 0 || extra != "" {
+		w.WriteByte('{')
+
+		for i := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+
+			w.WriteString(labels[i] + `="` + escape(values[i]) + `"`)
+		}
+
+		if extra != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+
+			w.WriteString(extra + `="` + extraVal + `"`)
+		}
+
+		w.WriteByte('}')
+	}
+
+	w.WriteString(" " + formatFloat(v) + "\n")
+}
+
+var _escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string { return _escaper.Replace(s) }
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
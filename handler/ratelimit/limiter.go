@@ -0,0 +1,171 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+type (
+	// Result hold the outcome of a limiter decision.
+	Result struct {
+		// Reset hold the delay until the quota is fully restored.
+		Reset time.Duration
+
+		// RetryAfter hold the delay before a denied request may be retried.
+		RetryAfter time.Duration
+
+		// Limit hold the quota.
+		Limit int
+
+		// Remaining hold the quota left.
+		Remaining int
+
+		// Allowed is false if the request exceeded the quota.
+		Allowed bool
+	}
+
+	// Limiter decide whether the request identified by key may proceed.
+	Limiter interface {
+		Allow(ctx context.Context, key string) (Result, error)
+	}
+
+	// tokenBucket refill limit tokens per period, each request taking one.
+	tokenBucket struct {
+		store  Store
+		period time.Duration
+		limit  int
+	}
+
+	// slidingWindow allow limit requests per window, the previous window
+	// hits being weighted by its overlap with the sliding one.
+	slidingWindow struct {
+		store  Store
+		window time.Duration
+		limit  int
+	}
+)
+
+// NewTokenBucket return a token bucket Limiter holding up to limit tokens,
+// fully refilled every period. A nil store default to a NewMemoryStore.
+func NewTokenBucket(limit int, period time.Duration, store Store) Limiter {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	return &tokenBucket{store: store, period: period, limit: limit}
+}
+
+// NewSlidingWindow return a sliding window Limiter allowing limit requests
+// per window. A nil store default to a NewMemoryStore.
+func NewSlidingWindow(limit int, window time.Duration, store Store) Limiter {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	return &slidingWindow{store: store, window: window, limit: limit}
+}
+
+// Allow implement Limiter.
+func (tb *tokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	var (
+		now   = time.Now()
+		limit = float64(tb.limit)
+		rate  = limit / tb.period.Seconds()
+		ret   = Result{Limit: tb.limit}
+	)
+
+	e := tb.store.Update(ctx, key, tb.period, func(s *State) {
+		if s.Stamp.IsZero() {
+			s.Tokens = limit
+		} else {
+			s.Tokens = math.Min(limit, s.Tokens+now.Sub(s.Stamp).Seconds()*rate)
+		}
+
+		s.Stamp = now
+
+		if s.Tokens >= 1 {
+			s.Tokens--
+			ret.Allowed = true
+		} else {
+			ret.RetryAfter = seconds((1 - s.Tokens) / rate)
+		}
+
+		ret.Remaining = int(s.Tokens)
+		ret.Reset = seconds((limit - s.Tokens) / rate)
+	})
+	if e != nil {
+		return ret, fmt.Errorf("token bucket: %w", e)
+	}
+
+	return ret, nil
+}
+
+// Allow implement Limiter.
+func (sw *slidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	var (
+		now   = time.Now()
+		start = now.Truncate(sw.window)
+		limit = float64(sw.limit)
+		ret   = Result{Limit: sw.limit, Reset: start.Add(sw.window).Sub(now)}
+	)
+
+	e := sw.store.Update(ctx, key, 2*sw.window, func(s *State) {
+		switch {
+		case s.Stamp.Equal(start):
+		case s.Stamp.Equal(start.Add(-sw.window)):
+			s.Stamp, s.PrevCount, s.Count = start, s.Count, 0
+		default:
+			s.Stamp, s.PrevCount, s.Count = start, 0, 0
+		}
+
+		var (
+			elapsed = now.Sub(start)
+			weight  = 1 - float64(elapsed)/float64(sw.window)
+			prev    = float64(s.PrevCount)
+			count   = float64(s.Count)
+		)
+
+		if prev*weight+count+1 <= limit {
+			s.Count++
+			count++
+			ret.Allowed = true
+		} else {
+			ret.RetryAfter = sw.retryAfter(elapsed, prev, count)
+		}
+
+		ret.Remaining = int(math.Max(0, limit-math.Ceil(prev*weight+count)))
+	})
+	if e != nil {
+		return ret, fmt.Errorf("sliding window: %w", e)
+	}
+
+	return ret, nil
+}
+
+// retryAfter return the delay until an extra hit fit the quota.
+func (sw *slidingWindow) retryAfter(elapsed time.Duration, prev, count float64) time.Duration {
+	var (
+		window = float64(sw.window)
+		free   = float64(sw.limit) - 1
+	)
+
+	// the previous window weight must decrease enough within the current window
+	if count <= free && prev > 0 {
+		return time.Duration(math.Ceil(window*(1-(free-count)/prev))) - elapsed
+	}
+
+	// wait for the next window, where the current one become the previous one
+	next := sw.window - elapsed
+	if count > 0 {
+		next += time.Duration(math.Ceil(math.Max(0, window*(1-free/count))))
+	}
+
+	return next
+}
+
+// seconds convert s seconds to a duration, rounded up.
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
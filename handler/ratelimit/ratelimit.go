@@ -0,0 +1,112 @@
+// Package ratelimit implement an handler limiting the requests rate, using
+// a token bucket or a sliding window algorithm.
+//
+// The handler may be registered server wide, per group or per route:
+//
+//	perIP := ratelimit.NewHandler(ratelimit.NewTokenBucket(100, time.Minute, nil), ratelimit.ByIP())
+//
+//	s, _ := webfmwk.InitServer(webfmwk.WithHandlers(perIP))
+//	s.Group("/login", ratelimit.NewHandler(
+//		ratelimit.NewSlidingWindow(5, time.Minute, nil), ratelimit.ByIP()))
+//	s.AddRoutes(webfmwk.Route{Verbe: webfmwk.POST, Path: "/upload", Handler: upload,
+//		Middlewares: &[]webfmwk.Handler{ratelimit.NewHandler(
+//			ratelimit.NewTokenBucket(10, time.Hour, nil), ratelimit.ByHeader("X-Api-Key"))}})
+//
+// Over-limit requests are answered a 429, with the RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and Retry-After headers.
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6"
+)
+
+// Headers set by the handler.
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// KeyFunc return the key identifying the request client.
+type KeyFunc func(c webfmwk.Context) string
+
+// ByIP identify the client by its IP, as returned by webfmwk.GetIPFromRequest.
+func ByIP() KeyFunc {
+	return func(c webfmwk.Context) string {
+		ip := webfmwk.GetIPFromRequest(c.GetFastContext())
+		if host, _, e := net.SplitHostPort(ip); e == nil {
+			return host
+		}
+
+		return ip
+	}
+}
+
+// ByHeader identify the client by the name header value.
+// The client IP is used if the header is missing.
+func ByHeader(name string) KeyFunc {
+	byIP := ByIP()
+
+	return func(c webfmwk.Context) string {
+		if v := c.GetFastContext().Request.Header.Peek(name); len(v) > 0 {
+			return name + ":" + string(v)
+		}
+
+		return byIP(c)
+	}
+}
+
+// ByClientCN identify the client by its mTLS certificate common name.
+// The client IP is used if no client certificate was presented.
+func ByClientCN() KeyFunc {
+	byIP := ByIP()
+
+	return func(c webfmwk.Context) string {
+		if cs := c.GetFastContext().TLSConnectionState(); cs != nil && len(cs.PeerCertificates) > 0 {
+			return "cn:" + cs.PeerCertificates[0].Subject.CommonName
+		}
+
+		return byIP(c)
+	}
+}
+
+// NewHandler return a webfmwk.Handler limiting the requests rate per key.
+// Limiters sharing a Store must be fed different keys.
+// If the limiter fail (ex: unreachable store) the request is let through.
+func NewHandler(l Limiter, key KeyFunc) webfmwk.Handler {
+	return func(next webfmwk.HandlerFunc) webfmwk.HandlerFunc {
+		return webfmwk.HandlerFunc(func(c webfmwk.Context) error {
+			res, e := l.Allow(c.GetContext(), key(c))
+			if e != nil {
+				c.GetStructuredLogger().Warn("rate limiter failure, request allowed", slog.Any("error", e))
+
+				return next(c)
+			}
+
+			c.SetHeaders(
+				webfmwk.Header{HeaderLimit, strconv.Itoa(res.Limit)},
+				webfmwk.Header{HeaderRemaining, strconv.Itoa(res.Remaining)},
+				webfmwk.Header{HeaderReset, ceilSeconds(res.Reset)})
+
+			if !res.Allowed {
+				c.SetHeader(HeaderRetryAfter, ceilSeconds(res.RetryAfter))
+
+				return webfmwk.NewTooManyRequests(webfmwk.NewError("too many requests"))
+			}
+
+			return next(c)
+		})
+	}
+}
+
+// ceilSeconds return the d duration in seconds, rounded up.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,172 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type failingStore struct{}
+
+func (failingStore) Update(context.Context, string, time.Duration, func(*State)) error {
+	return errors.New("unreachable")
+}
+
+func TestTokenBucket(t *testing.T) {
+	var (
+		ctx = context.Background()
+		l   = NewTokenBucket(2, time.Second, nil)
+	)
+
+	for i, remaining := range []int{1, 0} {
+		res, e := l.Allow(ctx, "k")
+		require.Nil(t, e)
+		assert.True(t, res.Allowed, "hit %d", i)
+		assert.Equal(t, remaining, res.Remaining)
+	}
+
+	res, e := l.Allow(ctx, "k")
+	require.Nil(t, e)
+	assert.False(t, res.Allowed)
+	assert.InDelta(t, 500*time.Millisecond, res.RetryAfter, float64(50*time.Millisecond))
+
+	t.Log("keys are limited independently")
+	{
+		res, e := l.Allow(ctx, "other")
+		require.Nil(t, e)
+		assert.True(t, res.Allowed)
+	}
+
+	t.Log("tokens are refilled over time")
+	{
+		time.Sleep(res.RetryAfter)
+
+		res, e := l.Allow(ctx, "k")
+		require.Nil(t, e)
+		assert.True(t, res.Allowed)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	var (
+		ctx = context.Background()
+		l   = NewSlidingWindow(3, time.Hour, nil)
+	)
+
+	for i := 0; i < 3; i++ {
+		res, e := l.Allow(ctx, "k")
+		require.Nil(t, e)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2-i, res.Remaining)
+	}
+
+	res, e := l.Allow(ctx, "k")
+	require.Nil(t, e)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Greater(t, res.RetryAfter, res.Reset)
+	assert.LessOrEqual(t, res.Reset, time.Hour)
+
+	t.Log("the previous window hits are weighted")
+	{
+		sw := &slidingWindow{window: time.Minute, limit: 10}
+
+		// 10 previous hits: a slot is freed once their weight drop to 90%
+		assert.Equal(t, 6*time.Second, sw.retryAfter(0, 10, 0))
+		// 10 previous hits and 4 current ones: 50% to free a slot
+		assert.Equal(t, 10*time.Second, sw.retryAfter(20*time.Second, 10, 4))
+		// quota reached in the current window: wait for the next one
+		assert.Equal(t, 56*time.Second, sw.retryAfter(10*time.Second, 0, 10))
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ms := NewMemoryStore(2)
+
+	for _, k := range []string{"a", "b", "c"} {
+		require.Nil(t, ms.Update(context.Background(), k, time.Millisecond, func(s *State) { s.Count++ }))
+	}
+
+	assert.Equal(t, 3, ms.Len())
+
+	time.Sleep(2 * time.Millisecond)
+
+	require.Nil(t, ms.Update(context.Background(), "a", time.Millisecond, func(s *State) {
+		assert.Zero(t, s.Count, "expired state is reset")
+	}))
+
+	assert.Less(t, ms.Len(), 3)
+}
+
+func TestHandler(t *testing.T) {
+	s, e := webfmwk.InitServer()
+	require.Nil(t, e)
+
+	var (
+		limited = NewHandler(NewSlidingWindow(1, time.Hour, nil), ByHeader("X-Api-Key"))
+		group   = s.Group("/group", limited)
+	)
+
+	group.GET("/a", func(c webfmwk.Context) error { return c.JSONOk("a") })
+	group.GET("/b", func(c webfmwk.Context) error { return c.JSONOk("b") })
+	s.AddRoutes(webfmwk.Route{
+		Verbe: webfmwk.GET, Path: "/route", Handler: func(c webfmwk.Context) error { return c.JSONOk("ok") },
+		Middlewares: &[]webfmwk.Handler{NewHandler(NewTokenBucket(1, time.Hour, nil), ByIP())},
+	})
+	s.AddRoutes(webfmwk.Route{
+		Verbe: webfmwk.GET, Path: "/failing", Handler: func(c webfmwk.Context) error { return c.JSONOk("ok") },
+		Middlewares: &[]webfmwk.Handler{NewHandler(NewTokenBucket(1, time.Hour, failingStore{}), ByIP())},
+	})
+	s.GET("/free", func(c webfmwk.Context) error { return c.JSONOk("ok") })
+
+	do := func(uri, key string) *fasthttp.RequestCtx {
+		fc := &fasthttp.RequestCtx{}
+		fc.Request.Header.SetMethod(webfmwk.GET)
+		fc.Request.SetRequestURI(uri)
+
+		if key != "" {
+			fc.Request.Header.Set("X-Api-Key", key)
+		}
+
+		s.GetRouter().Handler(fc)
+
+		return fc
+	}
+
+	t.Log("the group routes share the group limiter")
+	{
+		fc := do("/group/a", "k1")
+		require.Equal(t, http.StatusOK, fc.Response.StatusCode())
+		assert.Equal(t, "1", string(fc.Response.Header.Peek(HeaderLimit)))
+		assert.Equal(t, "0", string(fc.Response.Header.Peek(HeaderRemaining)))
+
+		fc = do("/group/b", "k1")
+		require.Equal(t, http.StatusTooManyRequests, fc.Response.StatusCode())
+		assert.JSONEq(t, `{"message":"too many requests","status":429}`, string(fc.Response.Body()))
+		assert.NotEmpty(t, fc.Response.Header.Peek(HeaderRetryAfter))
+		assert.NotEmpty(t, fc.Response.Header.Peek(HeaderReset))
+
+		assert.Equal(t, http.StatusOK, do("/group/b", "k2").Response.StatusCode())
+	}
+
+	t.Log("the route limiter is scoped to the route")
+	{
+		assert.Equal(t, http.StatusOK, do("/route", "").Response.StatusCode())
+		assert.Equal(t, http.StatusTooManyRequests, do("/route", "").Response.StatusCode())
+		assert.Equal(t, http.StatusOK, do("/free", "").Response.StatusCode())
+		assert.Empty(t, do("/free", "").Response.Header.Peek(HeaderLimit))
+	}
+
+	t.Log("limiter failures let the requests through")
+	{
+		assert.Equal(t, http.StatusOK, do("/failing", "").Response.StatusCode())
+		assert.Equal(t, http.StatusOK, do("/failing", "").Response.StatusCode())
+	}
+}
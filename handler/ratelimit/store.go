@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const _defShards = 64
+
+type (
+	// State hold the limiter state of a key.
+	State struct {
+		// Stamp hold the last refill time (token bucket) or the current
+		// window start (sliding window).
+		Stamp time.Time
+
+		// Tokens hold the available tokens of a token bucket.
+		Tokens float64
+
+		// Count and PrevCount hold the sliding window current and
+		// previous windows hits.
+		Count, PrevCount int64
+	}
+
+	// Store hold the limiters state. MemoryStore is the in-memory implementation;
+	// implement Store to share the state between instances via an external
+	// backend (ex: redis).
+	Store interface {
+		// Update atomically load the key state, apply fn on it and save it
+		// back. The key may be dropped once ttl elapsed without update.
+		Update(ctx context.Context, key string, ttl time.Duration, fn func(s *State)) error
+	}
+
+	// MemoryStore is a sharded in-memory Store. Expired keys are dropped lazily.
+	MemoryStore struct {
+		shards []*shard
+	}
+
+	shard struct {
+		next    time.Time
+		entries map[string]*entry
+		mu      sync.Mutex
+	}
+
+	entry struct {
+		expire time.Time
+		State
+	}
+)
+
+// NewMemoryStore return a MemoryStore. The number of shards default to 64.
+func NewMemoryStore(shards ...int) *MemoryStore {
+	n := _defShards
+	if len(shards) > 0 && shards[0] > 0 {
+		n = shards[0]
+	}
+
+	ms := &MemoryStore{shards: make([]*shard, n)}
+	for i := range ms.shards {
+		ms.shards[i] = &shard{entries: make(map[string]*entry)}
+	}
+
+	return ms
+}
+
+// Update implement Store.
+func (ms *MemoryStore) Update(_ context.Context, key string, ttl time.Duration, fn func(s *State)) error {
+	var (
+		now = time.Now()
+		sh  = ms.shard(key)
+	)
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.sweep(now, ttl)
+
+	ent, ok := sh.entries[key]
+	if !ok || now.After(ent.expire) {
+		ent = &entry{}
+		sh.entries[key] = ent
+	}
+
+	fn(&ent.State)
+	ent.expire = now.Add(ttl)
+
+	return nil
+}
+
+// Len return the number of stored keys, expired ones included.
+func (ms *MemoryStore) Len() (n int) {
+	for _, sh := range ms.shards {
+		sh.mu.Lock()
+		n += len(sh.entries)
+		sh.mu.Unlock()
+	}
+
+	return n
+}
+
+func (ms *MemoryStore) shard(key string) *shard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return ms.shards[h.Sum32()%uint32(len(ms.shards))]
+}
+
+// sweep drop the expired entries, at most once per ttl. Lock must be held.
+func (sh *shard) sweep(now time.Time, ttl time.Duration) {
+	if now.Before(sh.next) {
+		return
+	}
+
+	for k, ent := range sh.entries {
+		if now.After(ent.expire) {
+			delete(sh.entries, k)
+		}
+	}
+
+	sh.next = now.Add(ttl)
+}
@@ -19,8 +19,8 @@ func Handler(next webfmwk.HandlerFunc) webfmwk.HandlerFunc {
 
 				default:
 					c.GetStructuredLogger().Error("catched exit", "error", e)
-					_ = c.JSONInternalError(webfmwk.NewError(
-						fmt.Sprintf("internal error: %T %v", e, e)))
+					webfmwk.HandleError(c, webfmwk.NewInternal(webfmwk.NewError(
+						fmt.Sprintf("internal error: %T %v", e, e))))
 				}
 			}
 		}()
@@ -11,7 +11,7 @@ import (
 
 const (
 	// HeaderRequestID hold the header name to which the RIP is attached
-	HeaderRequestID = "X-Request-Id"
+	HeaderRequestID = webfmwk.HeaderRequestID
 	_limitOutput    = 2048
 )
 
@@ -40,14 +40,16 @@ func NewHandler(method ...func(*slog.Logger, string, ...any)) webfmwk.Handler {
 			}
 			c.SetHeader(HeaderRequestID, rid)
 
-			lg := c.GetStructuredLogger().With(
-				slog.Group("request",
-					slog.String("id", rid),
-					slog.String("ip", webfmwk.GetIPFromRequest(fc)),
-					slog.String("method", string(fc.Method())),
-					slog.String("uri", string(fc.RequestURI()))))
+			group := slog.Group("request",
+				slog.String("id", rid),
+				slog.String("ip", webfmwk.GetIPFromRequest(fc)),
+				slog.String("method", string(fc.Method())),
+				slog.String("uri", string(fc.RequestURI())))
 
-			c.SetStructuredLogger(lg)
+			// the access logs are filtered by the webfmwk.LogAccess level
+			lg := c.GetLogger(webfmwk.LogAccess).With(group)
+
+			c.SetStructuredLogger(c.GetStructuredLogger().With(group))
 
 			(mh)(lg, "--> new request")
 
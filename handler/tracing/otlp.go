@@ -0,0 +1,407 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	_defBatchSize = 512
+	_defQueueSize = 2048
+	_defInterval  = 5 * time.Second
+	_scopeName    = "github.com/burgesQ/webfmwk/v6/handler/tracing"
+	_spanKindSrv  = 2
+)
+
+type (
+	// OTLPParam hold the OTLPExporter settings.
+	OTLPParam struct {
+		// Client is the http client used to post the spans.
+		Client *http.Client
+
+		// Headers are added to each export request (ex: authentication).
+		Headers map[string]string
+
+		// ServiceName is exported as the service.name resource attribute.
+		ServiceName string
+
+		// BatchSize is the maximum number of spans per export request.
+		// Default to 512 if not positive.
+		BatchSize int
+
+		// QueueSize is the number of spans buffered; extra spans are dropped.
+		// Default to 2048 if not positive.
+		QueueSize int
+
+		// OnError, if set, is called with the error of each failed export.
+		OnError func(error)
+
+		// Interval is the maximum delay between two export requests.
+		// Default to 5s if not positive.
+		Interval time.Duration
+	}
+
+	// OTLPExporter post the spans to an OpenTelemetry collector, using the
+	// OTLP/HTTP protocol with the JSON encoding. Spans are batched in the
+	// background; call Shutdown to flush the pending ones.
+	OTLPExporter struct {
+		ctx      context.Context //nolint:containedctx
+		cancel   context.CancelFunc
+		queue    chan *Span
+		done     chan struct{}
+		stopped  chan struct{}
+		endpoint string
+		p        OTLPParam
+		dropped  int
+		failed   int
+		mu       sync.Mutex
+		stop     sync.Once
+	}
+
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+	}
+
+	otlpAttribute struct {
+		Value otlpValue `json:"value"`
+		Key   string    `json:"key"`
+	}
+
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		TraceState        string          `json:"traceState,omitempty"`
+		Name              string          `json:"name"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Status            struct {
+			Code int `json:"code"`
+		} `json:"status"`
+		Kind int `json:"kind"`
+	}
+
+	otlpScopeSpans struct {
+		Scope struct {
+			Name string `json:"name"`
+		} `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+
+	otlpResourceSpans struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+)
+
+func defOTLPParam() *OTLPParam {
+	return &OTLPParam{
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		ServiceName: "webfmwk",
+		BatchSize:   _defBatchSize,
+		QueueSize:   _defQueueSize,
+		Interval:    _defInterval,
+	}
+}
+
+// ServiceName set the exported service.name resource attribute.
+func ServiceName(name string) func(*OTLPParam) {
+	return func(p *OTLPParam) {
+		p.ServiceName = name
+	}
+}
+
+// Headers set the headers added to each export request.
+func Headers(h map[string]string) func(*OTLPParam) {
+	return func(p *OTLPParam) {
+		p.Headers = h
+	}
+}
+
+// Client set the http client used to post the spans.
+func Client(c *http.Client) func(*OTLPParam) {
+	return func(p *OTLPParam) {
+		p.Client = c
+	}
+}
+
+// Batch set the export batch size and the maximum delay between two exports.
+func Batch(size int, interval time.Duration) func(*OTLPParam) {
+	return func(p *OTLPParam) {
+		p.BatchSize, p.Interval = size, interval
+	}
+}
+
+// OnError set the callback called with the error of each failed export.
+func OnError(fn func(error)) func(*OTLPParam) {
+	return func(p *OTLPParam) {
+		p.OnError = fn
+	}
+}
+
+// NewOTLPExporter return a started OTLPExporter posting to endpoint
+// (ex: http://localhost:4318/v1/traces).
+func NewOTLPExporter(endpoint string, opt ...func(*OTLPParam)) *OTLPExporter {
+	p := defOTLPParam()
+
+	for _, o := range opt {
+		o(p)
+	}
+
+	if p.BatchSize <= 0 {
+		p.BatchSize = _defBatchSize
+	}
+
+	if p.QueueSize <= 0 {
+		p.QueueSize = _defQueueSize
+	}
+
+	if p.Interval <= 0 {
+		p.Interval = _defInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	exp := &OTLPExporter{
+		ctx:      ctx,
+		cancel:   cancel,
+		queue:    make(chan *Span, p.QueueSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		endpoint: endpoint,
+		p:        *p,
+	}
+
+	go exp.run()
+
+	return exp
+}
+
+// ExportSpan implement Exporter. The span is dropped if the queue is full.
+func (exp *OTLPExporter) ExportSpan(s *Span) {
+	select {
+	case exp.queue <- s:
+	default:
+		exp.mu.Lock()
+		exp.dropped++
+		exp.mu.Unlock()
+	}
+}
+
+// Dropped return the number of spans dropped because the queue was full.
+func (exp *OTLPExporter) Dropped() int {
+	exp.mu.Lock()
+	defer exp.mu.Unlock()
+
+	return exp.dropped
+}
+
+// Failed return the number of spans lost because their export failed
+// (ex: unreachable collector or non 2xx response), see OnError.
+func (exp *OTLPExporter) Failed() int {
+	exp.mu.Lock()
+	defer exp.mu.Unlock()
+
+	return exp.failed
+}
+
+// Shutdown stop the exporter, flushing the pending spans until ctx is done.
+// The export in progress is then aborted, and the remaining spans counted
+// as failed.
+func (exp *OTLPExporter) Shutdown(ctx context.Context) error {
+	exp.stop.Do(func() { close(exp.done) })
+
+	select {
+	case <-exp.stopped:
+		exp.cancel()
+
+		return nil
+	case <-ctx.Done():
+		exp.cancel()
+		<-exp.stopped
+
+		return fmt.Errorf("flushing the spans: %w", ctx.Err())
+	}
+}
+
+func (exp *OTLPExporter) run() {
+	defer close(exp.stopped)
+
+	var (
+		batch  = make([]*Span, 0, exp.p.BatchSize)
+		ticker = time.NewTicker(exp.p.Interval)
+	)
+
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) > 0 {
+			if e := exp.export(exp.ctx, batch); e != nil {
+				exp.mu.Lock()
+				exp.failed += len(batch)
+				exp.mu.Unlock()
+
+				if exp.p.OnError != nil {
+					exp.p.OnError(e)
+				}
+			}
+
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case s := <-exp.queue:
+			if batch = append(batch, s); len(batch) >= exp.p.BatchSize {
+				flush()
+			}
+
+		case <-ticker.C:
+			flush()
+
+		case <-exp.done:
+			for {
+				select {
+				case s := <-exp.queue:
+					if batch = append(batch, s); len(batch) >= exp.p.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+
+					return
+				}
+			}
+		}
+	}
+}
+
+// export post the spans batch to the collector.
+func (exp *OTLPExporter) export(ctx context.Context, spans []*Span) error {
+	body, e := json.Marshal(exp.toRequest(spans))
+	if e != nil {
+		return fmt.Errorf("encoding the spans: %w", e)
+	}
+
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, exp.endpoint, bytes.NewReader(body))
+	if e != nil {
+		return fmt.Errorf("creating the export request: %w", e)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for k, v := range exp.p.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, e := exp.p.Client.Do(req)
+	if e != nil {
+		return fmt.Errorf("posting the spans: %w", e)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("posting the spans: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// toRequest convert the spans to an OTLP ExportTraceServiceRequest.
+func (exp *OTLPExporter) toRequest(spans []*Span) otlpRequest {
+	var (
+		rs = otlpResourceSpans{ScopeSpans: make([]otlpScopeSpans, 1)}
+		ss = &rs.ScopeSpans[0]
+	)
+
+	rs.Resource.Attributes = toAttributes(map[string]interface{}{"service.name": exp.p.ServiceName})
+	ss.Scope.Name = _scopeName
+	ss.Spans = make([]otlpSpan, len(spans))
+
+	for i, s := range spans {
+		s.mu.Lock()
+
+		span := otlpSpan{
+			TraceID:           s.TraceID.String(),
+			SpanID:            s.SpanID.String(),
+			TraceState:        s.TraceState,
+			Name:              s.Name,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        toAttributes(s.Attributes),
+			Kind:              _spanKindSrv,
+		}
+
+		s.mu.Unlock()
+
+		if s.Parent.IsValid() {
+			span.ParentSpanID = s.Parent.String()
+		}
+
+		span.Status.Code = s.Status
+		ss.Spans[i] = span
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{rs}}
+}
+
+// toAttributes convert the attrs map to sorted OTLP attributes.
+func toAttributes(attrs map[string]interface{}) []otlpAttribute {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	ret := make([]otlpAttribute, len(keys))
+
+	for i, k := range keys {
+		ret[i] = otlpAttribute{Key: k, Value: toValue(attrs[k])}
+	}
+
+	return ret
+}
+
+func toValue(v interface{}) otlpValue {
+	switch val := v.(type) {
+	case string:
+		return otlpValue{StringValue: &val}
+	case bool:
+		return otlpValue{BoolValue: &val}
+	case int:
+		i := strconv.Itoa(val)
+
+		return otlpValue{IntValue: &i}
+	case int64:
+		i := strconv.FormatInt(val, 10)
+
+		return otlpValue{IntValue: &i}
+	case float64:
+		return otlpValue{DoubleValue: &val}
+	default:
+		str := fmt.Sprint(val)
+
+		return otlpValue{StringValue: &str}
+	}
+}
@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	// HeaderTraceParent hold the W3C trace context traceparent header name.
+	HeaderTraceParent = "traceparent"
+
+	// HeaderTraceState hold the W3C trace context tracestate header name.
+	HeaderTraceState = "tracestate"
+
+	_version     = "00"
+	_flagSampled = 0x01
+)
+
+// ErrInvalidTraceParent is returned when a traceparent header can't be parsed.
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+type (
+	// TraceID is a W3C trace context trace-id.
+	TraceID [16]byte
+
+	// SpanID is a W3C trace context parent-id.
+	SpanID [8]byte
+
+	// SpanContext hold the propagated part of a span.
+	SpanContext struct {
+		// TraceState hold the vendor specific tracestate header value, forwarded as is.
+		TraceState string
+		TraceID    TraceID
+		SpanID     SpanID
+		Flags      byte
+	}
+)
+
+// String return the hex encoded trace ID.
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsValid return false for the all zero trace ID.
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// String return the hex encoded span ID.
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsValid return false for the all zero span ID.
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// IsSampled return true if the sampled flag is set.
+func (sc SpanContext) IsSampled() bool { return sc.Flags&_flagSampled != 0 }
+
+// TraceParent return the traceparent header value of the span context.
+func (sc SpanContext) TraceParent() string {
+	return _version + "-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + hex.EncodeToString([]byte{sc.Flags})
+}
+
+// ParseTraceParent parse a traceparent header value.
+// Future versions are accepted, as long as the version 00 fields are valid.
+func ParseTraceParent(v string) (SpanContext, error) {
+	var (
+		sc    SpanContext
+		parts = strings.Split(strings.TrimSpace(v), "-")
+	)
+
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		(parts[0] == _version && len(parts) != 4) {
+		return sc, ErrInvalidTraceParent
+	}
+
+	var flags [1]byte
+
+	if e := decodeHex(sc.TraceID[:], parts[1]); e != nil {
+		return sc, e
+	} else if e = decodeHex(sc.SpanID[:], parts[2]); e != nil {
+		return sc, e
+	} else if e = decodeHex(flags[:], parts[3]); e != nil {
+		return sc, e
+	} else if !sc.TraceID.IsValid() || !sc.SpanID.IsValid() {
+		return sc, ErrInvalidTraceParent
+	}
+
+	sc.Flags = flags[0]
+
+	return sc, nil
+}
+
+// decodeHex decode the lower case hex encoded src in dst, which must be filled.
+func decodeHex(dst []byte, src string) error {
+	if len(src) != hex.EncodedLen(len(dst)) || strings.ToLower(src) != src {
+		return ErrInvalidTraceParent
+	}
+
+	if _, e := hex.Decode(dst, []byte(src)); e != nil {
+		return ErrInvalidTraceParent
+	}
+
+	return nil
+}
+
+func newTraceID() (t TraceID) {
+	for !t.IsValid() {
+		_, _ = rand.Read(t[:])
+	}
+
+	return t
+}
+
+func newSpanID() (s SpanID) {
+	for !s.IsValid() {
+		_, _ = rand.Read(s[:])
+	}
+
+	return s
+}
@@ -0,0 +1,183 @@
+// Package tracing implement an handler starting a span per request, compatible
+// with the W3C trace context propagation and the OpenTelemetry data model.
+//
+//	exp := tracing.NewOTLPExporter("http://collector:4318/v1/traces",
+//		tracing.ServiceName("api"))
+//	defer exp.Shutdown(context.Background())
+//
+//	s, _ := webfmwk.InitServer(webfmwk.WithHandlers(tracing.NewHandler(exp)))
+//
+// The span is carried by the request context:
+//
+//	span := tracing.SpanFromContext(c.GetContext())
+//	span.SetAttribute("user.id", id)
+package tracing
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6"
+)
+
+// Span status code, matching the OpenTelemetry ones.
+const (
+	StatusUnset = iota
+	StatusOK
+	StatusError
+)
+
+type (
+	// Span hold a request trace span.
+	Span struct {
+		Start      time.Time
+		End        time.Time
+		Attributes map[string]interface{}
+		Name       string
+		SpanContext
+		Parent SpanID
+		Status int
+		mu     sync.Mutex
+	}
+
+	// Exporter receive the ended spans. ExportSpan must not block.
+	Exporter interface {
+		ExportSpan(s *Span)
+	}
+
+	// InMemoryExporter keep the exported spans in memory, mostly for testing purpose.
+	InMemoryExporter struct {
+		spans []*Span
+		mu    sync.Mutex
+	}
+
+	spanKey struct{}
+)
+
+// SetAttribute set the k attribute of the span.
+func (s *Span) SetAttribute(k string, v interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]interface{})
+	}
+
+	s.Attributes[k] = v
+}
+
+// Attribute return the k attribute of the span.
+func (s *Span) Attribute(k string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.Attributes[k]
+
+	return v, ok
+}
+
+// ContextWithSpan return a copy of ctx carrying the span.
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, s)
+}
+
+// SpanFromContext return the span carried by ctx, nil if none.
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+
+	return s
+}
+
+// ExportSpan implement Exporter.
+func (ime *InMemoryExporter) ExportSpan(s *Span) {
+	ime.mu.Lock()
+	defer ime.mu.Unlock()
+
+	ime.spans = append(ime.spans, s)
+}
+
+// Spans return the exported spans.
+func (ime *InMemoryExporter) Spans() []*Span {
+	ime.mu.Lock()
+	defer ime.mu.Unlock()
+
+	return append([]*Span(nil), ime.spans...)
+}
+
+// Reset drop the exported spans.
+func (ime *InMemoryExporter) Reset() {
+	ime.mu.Lock()
+	defer ime.mu.Unlock()
+
+	ime.spans = nil
+}
+
+// NewHandler return a webfmwk.Handler starting a span per request.
+//
+// The parent span is read from the traceparent and tracestate headers, and
+// the server span is emitted back via the same headers. The span is named
+// after the method and the matched route template (ex: GET /users/{id}).
+// The trace and span IDs are attached to the context structured logger.
+// Only the sampled spans are exported; new traces are always sampled.
+func NewHandler(exp Exporter) webfmwk.Handler {
+	return func(next webfmwk.HandlerFunc) webfmwk.HandlerFunc {
+		return webfmwk.HandlerFunc(func(c webfmwk.Context) error {
+			var (
+				fc     = c.GetFastContext()
+				method = string(fc.Method())
+				span   = &Span{Start: time.Now(), Name: method}
+			)
+
+			if parent, e := ParseTraceParent(string(fc.Request.Header.Peek(HeaderTraceParent))); e == nil {
+				span.SpanContext = parent
+				span.Parent = parent.SpanID
+				span.TraceState = string(fc.Request.Header.Peek(HeaderTraceState))
+			} else {
+				span.TraceID, span.Flags = newTraceID(), _flagSampled
+			}
+
+			span.SpanID = newSpanID()
+			span.Attributes = map[string]interface{}{
+				"http.request.method": method,
+				"url.path":            string(fc.Path()),
+			}
+
+			if r := c.GetRoute(); r != nil {
+				span.Name += " " + r.Path
+				span.Attributes["http.route"] = r.Path
+			}
+
+			c.SetHeader(HeaderTraceParent, span.TraceParent())
+
+			if span.TraceState != "" {
+				c.SetHeader(HeaderTraceState, span.TraceState)
+			}
+
+			c.SetStructuredLogger(c.GetStructuredLogger().With(
+				slog.String("trace_id", span.TraceID.String()),
+				slog.String("span_id", span.SpanID.String())))
+			c.SetContext(ContextWithSpan(c.GetContext(), span))
+
+			e := next(c)
+
+			status := fc.Response.StatusCode()
+
+			span.SetAttribute("http.response.status_code", status)
+
+			if e != nil || status >= http.StatusInternalServerError {
+				span.Status = StatusError
+			}
+
+			span.End = time.Now()
+
+			if span.IsSampled() {
+				exp.ExportSpan(span)
+			}
+
+			return e
+		})
+	}
+}
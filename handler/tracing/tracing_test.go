@@ -0,0 +1,236 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+const _parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tests := map[string]struct {
+		in string
+		ok bool
+	}{
+		"valid":          {_parent, true},
+		"future version": {"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		"extra field":    {_parent + "-extra", false},
+		"version ff":     {"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		"upper case":     {"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		"zero trace id":  {"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		"zero span id":   {"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		"short":          {"00-4bf92f35-00f067aa0ba902b7-01", false},
+		"empty":          {"", false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sc, e := ParseTraceParent(test.in)
+			if !test.ok {
+				assert.ErrorIs(t, e, ErrInvalidTraceParent)
+
+				return
+			}
+
+			require.Nil(t, e)
+			assert.True(t, sc.IsSampled())
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+			assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var (
+		exp  = &InMemoryExporter{}
+		s, e = webfmwk.InitServer(webfmwk.WithHandlers(NewHandler(exp)))
+	)
+
+	require.Nil(t, e)
+
+	s.GET("/users/{id}", func(c webfmwk.Context) error {
+		span := SpanFromContext(c.GetContext())
+		if span == nil {
+			return webfmwk.NewInternal(webfmwk.NewError("no span"))
+		}
+
+		span.SetAttribute("user.id", c.GetVar("id"))
+
+		return c.JSONOk(span.TraceID.String())
+	})
+
+	do := func(headers map[string]string) *fasthttp.RequestCtx {
+		fc := &fasthttp.RequestCtx{}
+		fc.Request.Header.SetMethod(webfmwk.GET)
+		fc.Request.SetRequestURI("/users/42")
+
+		for k, v := range headers {
+			fc.Request.Header.Set(k, v)
+		}
+
+		s.GetRouter().Handler(fc)
+
+		return fc
+	}
+
+	t.Log("the incoming trace context is continued")
+	{
+		fc := do(map[string]string{HeaderTraceParent: _parent, HeaderTraceState: "vendor=value"})
+		require.Equal(t, http.StatusOK, fc.Response.StatusCode())
+		assert.Equal(t, `"4bf92f3577b34da6a3ce929d0e0e4736"`, string(fc.Response.Body()))
+
+		spans := exp.Spans()
+		require.Len(t, spans, 1)
+
+		span := spans[0]
+		assert.Equal(t, "GET /users/{id}", span.Name)
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent.String())
+		assert.NotEqual(t, span.Parent, span.SpanID)
+		assert.Equal(t, "vendor=value", span.TraceState)
+		assert.Equal(t, StatusUnset, span.Status)
+
+		v, _ := span.Attribute("user.id")
+		assert.Equal(t, "42", v)
+		v, _ = span.Attribute("http.route")
+		assert.Equal(t, "/users/{id}", v)
+
+		assert.Equal(t, span.TraceParent(), string(fc.Response.Header.Peek(HeaderTraceParent)))
+		assert.Equal(t, "vendor=value", string(fc.Response.Header.Peek(HeaderTraceState)))
+	}
+
+	exp.Reset()
+
+	t.Log("a new trace is started without a valid traceparent")
+	{
+		fc := do(map[string]string{HeaderTraceParent: "garbage"})
+		require.Equal(t, http.StatusOK, fc.Response.StatusCode())
+
+		spans := exp.Spans()
+		require.Len(t, spans, 1)
+		assert.NotEqual(t, `"4bf92f3577b34da6a3ce929d0e0e4736"`, string(fc.Response.Body()))
+		assert.False(t, spans[0].Parent.IsValid())
+		assert.True(t, spans[0].IsSampled())
+	}
+
+	exp.Reset()
+
+	t.Log("not sampled traces are propagated but not exported")
+	{
+		fc := do(map[string]string{HeaderTraceParent: _parent[:len(_parent)-2] + "00"})
+		require.Equal(t, http.StatusOK, fc.Response.StatusCode())
+		assert.Empty(t, exp.Spans())
+		assert.Contains(t, string(fc.Response.Header.Peek(HeaderTraceParent)), "4bf92f3577b34da6a3ce929d0e0e4736")
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	received := make(chan otlpRequest, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body otlpRequest
+
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+
+		received <- body
+	}))
+	defer srv.Close()
+
+	exp := NewOTLPExporter(srv.URL, ServiceName("test"),
+		Headers(map[string]string{"Authorization": "secret"}), Batch(10, time.Hour))
+
+	parent, e := ParseTraceParent(_parent)
+	require.Nil(t, e)
+
+	exp.ExportSpan(&Span{
+		SpanContext: SpanContext{TraceID: parent.TraceID, SpanID: newSpanID()},
+		Parent:      parent.SpanID,
+		Name:        "GET /users/{id}",
+		Start:       time.Unix(0, 1),
+		End:         time.Unix(0, 2),
+		Attributes:  map[string]interface{}{"http.response.status_code": 200},
+		Status:      StatusError,
+	})
+
+	require.Nil(t, exp.Shutdown(context.Background()))
+
+	body := <-received
+	require.Len(t, body.ResourceSpans, 1)
+
+	rs := body.ResourceSpans[0]
+	assert.Equal(t, "service.name", rs.Resource.Attributes[0].Key)
+	assert.Equal(t, "test", *rs.Resource.Attributes[0].Value.StringValue)
+
+	span := rs.ScopeSpans[0].Spans[0]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID)
+	assert.Equal(t, "1", span.StartTimeUnixNano)
+	assert.Equal(t, StatusError, span.Status.Code)
+	assert.Equal(t, _spanKindSrv, span.Kind)
+	assert.Equal(t, "200", *span.Attributes[0].Value.IntValue)
+}
+
+func TestOTLPExporterFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var errs []error
+
+	exp := NewOTLPExporter(srv.URL, Batch(10, time.Hour), OnError(func(e error) { errs = append(errs, e) }))
+
+	for i := 0; i < 3; i++ {
+		exp.ExportSpan(&Span{SpanContext: SpanContext{SpanID: newSpanID()}, Start: time.Now(), End: time.Now()})
+	}
+
+	require.Nil(t, exp.Shutdown(context.Background()))
+
+	assert.Equal(t, 3, exp.Failed())
+	assert.Equal(t, 0, exp.Dropped())
+	require.Len(t, errs, 1)
+}
+
+func TestOTLPExporterShutdown(t *testing.T) {
+	var (
+		hang = make(chan struct{})
+		srv  = httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			select {
+			case <-hang:
+			case <-r.Context().Done():
+			}
+		}))
+	)
+
+	defer srv.Close()
+	defer close(hang)
+
+	t.Log("the invalid batch settings fall back to the defaults")
+
+	exp := NewOTLPExporter(srv.URL, Batch(0, 0))
+	assert.Equal(t, _defBatchSize, exp.p.BatchSize)
+	assert.Equal(t, _defInterval, exp.p.Interval)
+
+	exp.ExportSpan(&Span{SpanContext: SpanContext{SpanID: newSpanID()}, Start: time.Now(), End: time.Now()})
+
+	t.Log("the shutdown context abort the hanging export")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, exp.Shutdown(ctx), context.DeadlineExceeded)
+	assert.Equal(t, 1, exp.Failed())
+}
@@ -0,0 +1,296 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Liveness checks are run by the /livez endpoint.
+	Liveness HealthKind = 1 << iota
+	// Readiness checks are run by the /readyz endpoint.
+	Readiness
+	// Startup checks are run by the /readyz endpoint until they succeed once.
+	Startup
+
+	// HealthOK is reported when all the checks succeed.
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded is reported when only non critical checks fail.
+	HealthDegraded HealthStatus = "degraded"
+	// HealthFailing is reported when a critical check fail.
+	HealthFailing HealthStatus = "failing"
+
+	_livezEndpoint   = "/livez"
+	_readyzEndpoint  = "/readyz"
+	_healthzEndpoint = "/healthz"
+
+	_defaultHealthTimeout = 5 * time.Second
+	_drainingCheck        = "shutdown"
+)
+
+// ErrDuplicateCheck is returned when a health check name is already registered.
+var ErrDuplicateCheck = errors.New("health check already registered")
+
+type (
+	// HealthKind flag the endpoints running a HealthCheck.
+	HealthKind uint8
+
+	// HealthStatus hold the status of a check or of an endpoint.
+	HealthStatus string
+
+	// HealthCheck hold a named check registered in the server HealthRegistry.
+	HealthCheck struct {
+		// Check is the function run by the health endpoints. A non nil error
+		// mark the check as failing.
+		Check func(ctx context.Context) error
+
+		// Name identify the check.
+		Name string
+
+		// Timeout bound the Check run, default to 5 seconds.
+		Timeout time.Duration
+
+		// Kind flag the endpoints running the check, default to Readiness.
+		// /healthz run all the checks.
+		Kind HealthKind
+
+		// Critical checks make the endpoint fail, the other only degrade it.
+		Critical bool
+	}
+
+	// CheckResult hold the result of a HealthCheck run.
+	CheckResult struct {
+		Error    string       `json:"error,omitempty"`
+		Status   HealthStatus `json:"status"`
+		Duration string       `json:"duration"`
+		Critical bool         `json:"critical"`
+	}
+
+	// HealthReport hold the aggregated result of the checks.
+	HealthReport struct {
+		Checks map[string]CheckResult `json:"checks,omitempty"`
+		Status HealthStatus           `json:"status"`
+	}
+
+	// HealthRegistry hold the health checks of a Server.
+	HealthRegistry struct {
+		checks  map[string]HealthCheck
+		started map[string]bool
+		mu      sync.RWMutex
+	}
+)
+
+func newHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{
+		checks:  make(map[string]HealthCheck),
+		started: make(map[string]bool),
+	}
+}
+
+// Register add the hc check. ErrDuplicateCheck is returned if the name is
+// already used.
+func (hr *HealthRegistry) Register(hc HealthCheck) error {
+	if hc.Name == "" || hc.Check == nil {
+		return errors.New("health check: missing name or check function")
+	}
+
+	if hc.Timeout <= 0 {
+		hc.Timeout = _defaultHealthTimeout
+	}
+
+	if hc.Kind == 0 {
+		hc.Kind = Readiness
+	}
+
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	if _, ok := hr.checks[hc.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateCheck, hc.Name)
+	}
+
+	hr.checks[hc.Name] = hc
+
+	return nil
+}
+
+// Unregister remove the name check.
+func (hr *HealthRegistry) Unregister(name string) {
+	hr.mu.Lock()
+	defer hr.mu.Unlock()
+
+	delete(hr.checks, name)
+	delete(hr.started, name)
+}
+
+// Names return the registered checks name, sorted.
+func (hr *HealthRegistry) Names() []string {
+	hr.mu.RLock()
+	defer hr.mu.RUnlock()
+
+	ret := make([]string, 0, len(hr.checks))
+	for name := range hr.checks {
+		ret = append(ret, name)
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
+
+// Run run concurrently the checks flagged with one of the kind flags and
+// aggregate their results. A zero kind run all the checks.
+// The Startup checks which already succeeded aren't run again.
+func (hr *HealthRegistry) Run(ctx context.Context, kind HealthKind) HealthReport {
+	hr.mu.RLock()
+
+	checks := make([]HealthCheck, 0, len(hr.checks))
+
+	for _, hc := range hr.checks {
+		if kind == 0 || hc.Kind&kind != 0 {
+			checks = append(checks, hc)
+		}
+	}
+
+	hr.mu.RUnlock()
+
+	var (
+		report = HealthReport{Status: HealthOK, Checks: make(map[string]CheckResult, len(checks))}
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	)
+
+	for i := range checks {
+		wg.Add(1)
+
+		go func(hc HealthCheck) {
+			defer wg.Done()
+
+			res := hr.run(ctx, hc)
+
+			mu.Lock()
+			report.add(hc.Name, res)
+			mu.Unlock()
+		}(checks[i])
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// run run the hc check, or report it if it's an already succeeded
+// startup check.
+func (hr *HealthRegistry) run(ctx context.Context, hc HealthCheck) CheckResult {
+	res := CheckResult{Status: HealthOK, Critical: hc.Critical, Duration: "0s"}
+
+	if hc.Kind&Startup != 0 {
+		hr.mu.RLock()
+		done := hr.started[hc.Name]
+		hr.mu.RUnlock()
+
+		if done {
+			return res
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hc.Timeout)
+	defer cancel()
+
+	var (
+		start = time.Now()
+		errc  = make(chan error, 1)
+		e     error
+	)
+
+	go func() { errc <- hc.Check(ctx) }()
+
+	select {
+	case e = <-errc:
+	case <-ctx.Done():
+		e = fmt.Errorf("timeout after %s", hc.Timeout)
+	}
+
+	res.Duration = time.Since(start).String()
+
+	if e != nil {
+		res.Status, res.Error = HealthFailing, e.Error()
+
+		return res
+	}
+
+	if hc.Kind&Startup != 0 {
+		hr.mu.Lock()
+		hr.started[hc.Name] = true
+		hr.mu.Unlock()
+	}
+
+	return res
+}
+
+// add save the res result of the name check and update the report status.
+func (r *HealthReport) add(name string, res CheckResult) {
+	r.Checks[name] = res
+
+	switch {
+	case res.Status == HealthOK:
+	case res.Critical:
+		r.Status = HealthFailing
+	case r.Status == HealthOK:
+		r.Status = HealthDegraded
+	}
+}
+
+// StatusCode return the http status code matching the report status.
+func (r HealthReport) StatusCode() int {
+	if r.Status == HealthFailing {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
+
+// Health return the server HealthRegistry.
+func (s *Server) Health() *HealthRegistry { return s.health }
+
+// WithHealthChecks register the checks and expose the /livez, /readyz and
+// /healthz endpoints, under the server prefix. The endpoints reply with the
+// aggregated status, detailed per check if the `verbose` query param is set.
+// /readyz fail once the server start its shutdown sequence.
+func WithHealthChecks(checks ...HealthCheck) Option {
+	return func(s *Server) {
+		s.meta.health = true
+
+		for i := range checks {
+			if e := s.health.Register(checks[i]); e != nil {
+				s.slog.Error("registering health check", "error", e)
+			}
+		}
+
+		s.slog.Debug("\t-- health checks loaded", "checks", len(checks))
+	}
+}
+
+// healthHandler return the handler of an health endpoint running the kind checks.
+func (s *Server) healthHandler(kind HealthKind) HandlerFunc {
+	return func(c Context) error {
+		report := s.health.Run(c.GetContext(), kind)
+
+		if (kind == 0 || kind&Readiness != 0) && s.IsDraining() {
+			report.add(_drainingCheck, CheckResult{
+				Status: HealthFailing, Error: "draining", Critical: true, Duration: "0s",
+			})
+		}
+
+		if !c.GetQuery().Has("verbose") {
+			report.Checks = nil
+		}
+
+		return c.JSON(report.StatusCode(), report)
+	}
+}
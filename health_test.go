@@ -0,0 +1,162 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func healthReport(t *testing.T, s *Server, uri string) (int, HealthReport) {
+	t.Helper()
+
+	var (
+		fc     = requestRouter(t, s, http.MethodGet, uri)
+		report HealthReport
+	)
+
+	require.Nil(t, json.Unmarshal(fc.Response.Body(), &report))
+
+	return fc.Response.StatusCode(), report
+}
+
+func TestHealthChecks(t *testing.T) {
+	var (
+		fail    = errors.New("db down")
+		dbErr   error
+		started bool
+		runs    int
+	)
+
+	s, e := InitServer(SetPrefix("/api"), WithHealthChecks(
+		HealthCheck{Name: "loop", Kind: Liveness, Critical: true,
+			Check: func(context.Context) error { return nil }},
+		HealthCheck{Name: "db", Critical: true,
+			Check: func(context.Context) error { return dbErr }},
+		HealthCheck{Name: "cache",
+			Check: func(context.Context) error { return fail }},
+		HealthCheck{Name: "warmup", Kind: Startup, Critical: true,
+			Check: func(context.Context) error {
+				runs++
+				if !started {
+					return errors.New("warming up")
+				}
+
+				return nil
+			}},
+	))
+	require.Nil(t, e)
+
+	t.Run("duplicate", func(t *testing.T) {
+		assert.ErrorIs(t, s.Health().Register(HealthCheck{Name: "db",
+			Check: func(context.Context) error { return nil }}), ErrDuplicateCheck)
+		assert.Equal(t, []string{"cache", "db", "loop", "warmup"}, s.Health().Names())
+	})
+
+	t.Run("livez", func(t *testing.T) {
+		code, report := healthReport(t, s, "/api/livez?verbose")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, HealthOK, report.Status)
+		assert.Len(t, report.Checks, 1)
+		assert.Equal(t, HealthOK, report.Checks["loop"].Status)
+	})
+
+	t.Run("startup pending", func(t *testing.T) {
+		code, report := healthReport(t, s, "/api/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, HealthFailing, report.Status)
+		assert.Nil(t, report.Checks)
+	})
+
+	t.Run("degraded", func(t *testing.T) {
+		started = true
+
+		code, report := healthReport(t, s, "/api/readyz?verbose")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, HealthDegraded, report.Status)
+		assert.Len(t, report.Checks, 3)
+		assert.Equal(t, "db down", report.Checks["cache"].Error)
+		assert.False(t, report.Checks["cache"].Critical)
+
+		// succeeded startup checks aren't run again
+		runs, started = 0, false
+		_, report = healthReport(t, s, "/api/readyz?verbose")
+		assert.Equal(t, HealthOK, report.Checks["warmup"].Status)
+		assert.Zero(t, runs)
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		dbErr = fail
+		defer func() { dbErr = nil }()
+
+		code, report := healthReport(t, s, "/api/healthz?verbose")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, HealthFailing, report.Status)
+		assert.Len(t, report.Checks, 4)
+		assert.Equal(t, HealthFailing, report.Checks["db"].Status)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		require.Nil(t, s.Health().Register(HealthCheck{
+			Name: "slow", Kind: Liveness, Critical: true, Timeout: 10 * time.Millisecond,
+			Check: func(ctx context.Context) error {
+				<-ctx.Done()
+				time.Sleep(50 * time.Millisecond)
+
+				return nil
+			},
+		}))
+		defer s.Health().Unregister("slow")
+
+		code, report := healthReport(t, s, "/api/livez?verbose")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Contains(t, report.Checks["slow"].Error, "timeout")
+	})
+
+	t.Run("combined startup", func(t *testing.T) {
+		var schemaRuns int
+
+		require.Nil(t, s.Health().Register(HealthCheck{
+			Name: "schema", Kind: Readiness | Startup, Critical: true,
+			Check: func(context.Context) error {
+				schemaRuns++
+
+				return nil
+			},
+		}))
+		defer s.Health().Unregister("schema")
+
+		for i := 0; i < 2; i++ {
+			code, _ := healthReport(t, s, "/api/readyz")
+			assert.Equal(t, http.StatusOK, code)
+		}
+
+		// a succeeded startup check isn't run again, whatever its other kinds
+		assert.Equal(t, 1, schemaRuns)
+	})
+
+	t.Run("draining", func(t *testing.T) {
+		s.draining.Store(true)
+		defer s.draining.Store(false)
+
+		code, report := healthReport(t, s, "/api/readyz?verbose")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, HealthFailing, report.Checks[_drainingCheck].Status)
+
+		code, _ = healthReport(t, s, "/api/livez")
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
+
+func TestHealthDisabled(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	fc := requestRouter(t, s, http.MethodGet, "/readyz")
+	assert.Equal(t, http.StatusNotFound, fc.Response.StatusCode())
+}
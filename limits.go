@@ -0,0 +1,213 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/router"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// HeaderRequestTimeout hold the header name via which a client may
+	// shorten the route handler deadline, in seconds or as a Go duration.
+	HeaderRequestTimeout = "Request-Timeout"
+
+	_limitsKey = "webfmwk::limits"
+)
+
+// RouteLimits hold the per route (or group) request limits, overriding the
+// server wide ones. A zero field inherit the group or server value.
+type RouteLimits struct {
+	// Timeout hold the handler deadline, reflected in Context.GetContext.
+	// The handler isn't interrupted: it must watch the context and give up
+	// once it's done, returning the context error which is answered a 503.
+	// The other responses, even written past the deadline, are kept. The
+	// client may shorten it via the Request-Timeout header, but never
+	// extend it.
+	Timeout time.Duration
+
+	// ReadTimeout bound the request body read. The route is only known once
+	// the headers are read, so the header timeout cannot be set per route:
+	// the headers read stay bounded by the server wide SetReadTimeout.
+	ReadTimeout time.Duration
+
+	// MaxBodySize hold the request body maximum size, lower or greater than
+	// the MaxRequestBodySize one. A greater body is answered a 413.
+	MaxBodySize int
+}
+
+// isZero return true if no limit is set.
+func (l RouteLimits) isZero() bool { return l == RouteLimits{} }
+
+// inherit return the l limits, the unset ones being taken from parent.
+func (l RouteLimits) inherit(parent RouteLimits) RouteLimits {
+	if l.Timeout <= 0 {
+		l.Timeout = parent.Timeout
+	}
+
+	if l.ReadTimeout <= 0 {
+		l.ReadTimeout = parent.ReadTimeout
+	}
+
+	if l.MaxBodySize <= 0 {
+		l.MaxBodySize = parent.MaxBodySize
+	}
+
+	return l
+}
+
+// WithLimits return a copy of the group applying the l limits to its routes,
+// the unset ones being inherited from the group.
+//
+//	uploads := s.Group("/files").WithLimits(webfmwk.RouteLimits{
+//		Timeout: 5 * time.Minute, ReadTimeout: time.Minute, MaxBodySize: 1 << 30})
+//	lookups := s.Group("/lookup").WithLimits(webfmwk.RouteLimits{Timeout: 200 * time.Millisecond})
+func (g *Group) WithLimits(l RouteLimits) *Group {
+	ret := *g
+	ret.limits = l.inherit(g.limits)
+
+	return &ret
+}
+
+// withLimits apply the l limits to the next handler. The deadline is
+// cooperative, the fasthttp.RequestCtx not being safe to answer while the
+// handler still use it, see deadlineError.
+func withLimits(l RouteLimits, next HandlerFunc) HandlerFunc {
+	return HandlerFunc(func(c Context) error {
+		if l.MaxBodySize > 0 && len(c.GetFastContext().Request.Body()) > l.MaxBodySize {
+			return NewErrorHandled(http.StatusRequestEntityTooLarge, NewError("request body too large"))
+		}
+
+		if l.Timeout <= 0 {
+			return next(c)
+		}
+
+		timeout := l.Timeout
+		if d, ok := parseRequestTimeout(c.GetFastContext().Request.Header.Peek(HeaderRequestTimeout)); ok {
+			timeout = min(timeout, d)
+		}
+
+		ctx, cancel := context.WithTimeout(c.GetContext(), timeout)
+		defer cancel()
+
+		return next(c.SetContext(ctx))
+	})
+}
+
+// deadlineError answer a 503 if the next route handler give up on its
+// deadline, returning the context error.
+func deadlineError(next HandlerFunc) HandlerFunc {
+	return HandlerFunc(func(c Context) error {
+		e := next(c)
+
+		if (errors.Is(e, context.DeadlineExceeded) || errors.Is(e, context.Canceled)) &&
+			c.GetContext().Err() != nil {
+			c.GetStructuredLogger().Warn("request deadline exceeded", "error", e)
+
+			return NewServiceUnavailable(NewError("request deadline exceeded"))
+		}
+
+		return e
+	})
+}
+
+// parseRequestTimeout parse the Request-Timeout header value, in seconds or
+// as a Go duration.
+func parseRequestTimeout(v []byte) (time.Duration, bool) {
+	if len(v) == 0 {
+		return 0, false
+	}
+
+	var d time.Duration
+
+	if sec, e := strconv.ParseFloat(string(v), 64); e == nil {
+		d = time.Duration(sec * float64(time.Second))
+	} else if d, e = time.ParseDuration(string(v)); e != nil {
+		return 0, false
+	}
+
+	return d, d > 0
+}
+
+// limitsRouter return a router resolving the routes read timeout and
+// maximum body size, or nil if no route declare them.
+func (s *Server) limitsRouter() *router.Router {
+	var r *router.Router
+
+	for prefix, routes := range s.meta.routes {
+		for i := range routes {
+			l := routes[i].Limits
+			if l.ReadTimeout <= 0 && l.MaxBodySize <= 0 {
+				continue
+			}
+
+			if r == nil {
+				r = router.New()
+			}
+
+			r.Handle(routes[i].Verbe, prefix+routes[i].Path, func(fc *fasthttp.RequestCtx) {
+				fc.SetUserValue(_limitsKey, l)
+			})
+		}
+	}
+
+	return r
+}
+
+// headerReceived return the fasthttp.Server.HeaderReceived callback applying
+// the matched route read timeout and maximum body size.
+func headerReceived(r *router.Router) func(*fasthttp.RequestHeader) fasthttp.RequestConfig {
+	pool := sync.Pool{New: func() any { return new(fasthttp.RequestCtx) }}
+
+	return func(h *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		fc, _ := pool.Get().(*fasthttp.RequestCtx)
+		defer func() {
+			fc.ResetUserValues()
+			pool.Put(fc)
+		}()
+
+		uri := fasthttp.AcquireURI()
+		defer fasthttp.ReleaseURI(uri)
+
+		if e := uri.Parse(nil, h.RequestURI()); e != nil {
+			return fasthttp.RequestConfig{}
+		}
+
+		handler, _ := r.Lookup(string(h.Method()), string(uri.Path()), fc)
+		if handler == nil {
+			return fasthttp.RequestConfig{}
+		}
+
+		handler(fc)
+
+		l, _ := fc.UserValue(_limitsKey).(RouteLimits)
+
+		return fasthttp.RequestConfig{ReadTimeout: l.ReadTimeout, MaxRequestBodySize: l.MaxBodySize}
+	}
+}
+
+// readErrorHandler answer the requests fasthttp failed to read, like the
+// fasthttp default one but for the too large bodies answered a 413.
+func readErrorHandler(fc *fasthttp.RequestCtx, err error) {
+	var (
+		small *fasthttp.ErrSmallBuffer
+		nerr  *net.OpError
+	)
+
+	switch {
+	case errors.Is(err, fasthttp.ErrBodyTooLarge):
+		fc.Error("Request body too large", http.StatusRequestEntityTooLarge)
+	case errors.As(err, &small):
+		fc.Error("Too big request header", http.StatusRequestHeaderFieldsTooLarge)
+	case errors.As(err, &nerr) && nerr.Timeout():
+		fc.Error("Request timeout", http.StatusRequestTimeout)
+	default:
+		fc.Error("Error when parsing request", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,169 @@
+package webfmwk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestRouteLimits(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	deadline := func(c Context) error {
+		d, ok := c.GetContext().Deadline()
+		if !ok {
+			return c.JSONOk("none")
+		}
+
+		return c.JSONOk(time.Until(d).Round(time.Second).String())
+	}
+
+	var (
+		lookups = s.Group("/lookup").WithLimits(RouteLimits{Timeout: time.Minute, MaxBodySize: 8})
+		nested  = lookups.Group("/nested").WithLimits(RouteLimits{Timeout: time.Hour})
+	)
+
+	lookups.GET("/deadline", deadline)
+	lookups.POST("/body", func(c Context) error { return c.JSONNoContent() })
+	lookups.GET("/slow", func(c Context) error {
+		<-c.GetContext().Done()
+
+		return c.GetContext().Err()
+	})
+	lookups.GET("/late", func(c Context) error {
+		<-c.GetContext().Done()
+
+		return c.JSONOk("late")
+	})
+	lookups.GET("/invalid", func(c Context) error {
+		<-c.GetContext().Done()
+
+		return NewBadRequest(NewError("invalid lookup"))
+	})
+	nested.GET("/deadline", deadline)
+	nested.AddRoutes(Route{Verbe: POST, Path: "/body", Limits: RouteLimits{MaxBodySize: 16},
+		Handler: func(c Context) error { return c.JSONNoContent() }})
+	s.GET("/deadline", deadline)
+
+	request := func(t *testing.T, method, uri, body string, headers ...string) *fasthttp.RequestCtx {
+		t.Helper()
+
+		fc := &fasthttp.RequestCtx{}
+		fc.Request.Header.SetMethod(method)
+		fc.Request.SetRequestURI(uri)
+		fc.Request.SetBodyString(body)
+		fc.Request.Header.SetContentType("application/json")
+
+		for i := 0; i+1 < len(headers); i += 2 {
+			fc.Request.Header.Set(headers[i], headers[i+1])
+		}
+
+		s.GetRouter().Handler(fc)
+
+		return fc
+	}
+
+	t.Run("deadline", func(t *testing.T) {
+		for uri, want := range map[string]string{
+			"/deadline":        `"none"`,
+			"/lookup/deadline": `"1m0s"`,
+			"/nested/deadline": `"1h0m0s"`,
+		} {
+			uri = strings.Replace(uri, "/nested", "/lookup/nested", 1)
+			fc := request(t, http.MethodGet, uri, "")
+			assert.Equal(t, http.StatusOK, fc.Response.StatusCode(), uri)
+			assert.Equal(t, want, string(fc.Response.Body()), uri)
+		}
+	})
+
+	t.Run("request timeout header", func(t *testing.T) {
+		for header, want := range map[string]string{
+			"10":    `"10s"`,
+			"2s":    `"2s"`,
+			"3600":  `"1m0s"`,
+			"-1":    `"1m0s"`,
+			"bogus": `"1m0s"`,
+		} {
+			fc := request(t, http.MethodGet, "/lookup/deadline", "", HeaderRequestTimeout, header)
+			assert.Equal(t, want, string(fc.Response.Body()), header)
+		}
+
+		start := time.Now()
+		fc := request(t, http.MethodGet, "/lookup/slow", "", HeaderRequestTimeout, "0.01")
+		assert.Equal(t, http.StatusServiceUnavailable, fc.Response.StatusCode())
+		assert.Less(t, time.Since(start), time.Second)
+
+		t.Log("a response written past the deadline is kept")
+
+		fc = request(t, http.MethodGet, "/lookup/late", "", HeaderRequestTimeout, "0.01")
+		assert.Equal(t, http.StatusOK, fc.Response.StatusCode())
+		assert.Equal(t, `"late"`, string(fc.Response.Body()))
+
+		t.Log("an error response written past the deadline is kept")
+
+		fc = request(t, http.MethodGet, "/lookup/invalid", "", HeaderRequestTimeout, "0.01")
+		assert.Equal(t, http.StatusBadRequest, fc.Response.StatusCode())
+	})
+
+	t.Run("body size", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, request(t, http.MethodPost, "/lookup/body", "12345678").Response.StatusCode())
+		assert.Equal(t, http.StatusRequestEntityTooLarge,
+			request(t, http.MethodPost, "/lookup/body", "123456789").Response.StatusCode())
+		assert.Equal(t, http.StatusNoContent,
+			request(t, http.MethodPost, "/lookup/nested/body", "123456789").Response.StatusCode())
+	})
+}
+
+func TestRouteLimitsServed(t *testing.T) {
+	var (
+		addr        = freeAddr(t)
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error, 1)
+	)
+
+	defer cancel()
+
+	s, e := InitServer(MaxRequestBodySize(16))
+	require.Nil(t, e)
+
+	s.POST("/small", func(c Context) error { return c.JSONNoContent() })
+	s.AddRoutes(Route{Verbe: POST, Path: "/upload/{name}", Limits: RouteLimits{MaxBodySize: 64},
+		Handler: func(c Context) error { return c.JSONNoContent() }})
+
+	go func() { done <- s.Serve(ctx, Address{Addr: addr}) }()
+
+	wctx, wcancel := context.WithTimeout(ctx, 5*time.Second)
+	defer wcancel()
+
+	require.Nil(t, s.WaitReady(wctx))
+
+	post := func(t *testing.T, path string, size int) int {
+		t.Helper()
+
+		req, e := http.NewRequestWithContext(ctx, http.MethodPost, "http://"+addr+path,
+			strings.NewReader(strings.Repeat("a", size)))
+		require.Nil(t, e)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, e := http.DefaultClient.Do(req)
+		require.Nil(t, e)
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusNoContent, post(t, "/small", 16))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(t, "/small", 32))
+	assert.Equal(t, http.StatusNoContent, post(t, "/upload/file?x=1", 32))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, post(t, "/upload/file", 128))
+
+	cancel()
+	require.Nil(t, <-done)
+}
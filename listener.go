@@ -0,0 +1,157 @@
+package webfmwk
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// Listener hold the metadata of a listener bound by a server.
+	Listener struct {
+		// Name hold the Address name, if any.
+		Name string `json:"name"`
+
+		// Addr hold the listening address or the unix socket path.
+		Addr string `json:"addr"`
+
+		// TLS is true for https listeners.
+		TLS bool `json:"tls"`
+
+		// HTTP2 is true for listeners with the http2 support.
+		HTTP2 bool `json:"http2"`
+
+		// Unix is true for unix socket listeners.
+		Unix bool `json:"unix"`
+
+		// Admin is true for the admin listener, see WithAdminAddress.
+		Admin bool `json:"admin"`
+	}
+
+	// listener bind a Listener to its fasthttp.Server.
+	listener struct {
+		ctx      context.Context //nolint:containedctx
+		cancel   context.CancelFunc
+		server   *fasthttp.Server
+		nl       net.Listener
+		raw      net.Listener
+		conns    *connTracker
+		cfg      tls.IConfig
+		reloader *tls.Reloader
+		Listener
+		// detached is true once the listener is stopped on its own
+		detached atomic.Bool
+	}
+
+	// onceCloseListener make the Close call of a net.Listener idempotent.
+	onceCloseListener struct {
+		net.Listener
+		once sync.Once
+		err  error
+	}
+
+	// listeners hold the listeners owned by a Server.
+	listeners struct {
+		all []*listener
+		mu  sync.Mutex
+	}
+)
+
+var (
+	// poolOfServers hold the Server instances with running listeners,
+	// so the global Shutdown can reach them
+	poolOfServers = make(map[*Server]struct{})
+	poolMu        sync.Mutex
+)
+
+// Close implement net.Listener.
+func (ol *onceCloseListener) Close() error {
+	ol.once.Do(func() { ol.err = ol.Listener.Close() })
+
+	return ol.err
+}
+
+// add register the l listener.
+func (ls *listeners) add(l *listener) int {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.all = append(ls.all, l)
+
+	return len(ls.all)
+}
+
+// remove unregister the l listener, returning false if it wasn't registered.
+func (ls *listeners) remove(l *listener) bool {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	for i := range ls.all {
+		if ls.all[i] == l {
+			ls.all = append(ls.all[:i], ls.all[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// snapshot return a copy of the registered listeners.
+func (ls *listeners) snapshot() []*listener {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	return append([]*listener(nil), ls.all...)
+}
+
+// list return a copy of the registered listeners metadata.
+func (ls *listeners) list() []Listener {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ret := make([]Listener, len(ls.all))
+	for i := range ls.all {
+		ret[i] = ls.all[i].Listener
+	}
+
+	return ret
+}
+
+// take unregister and return all the listeners.
+func (ls *listeners) take() []*listener {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ret := ls.all
+	ls.all = nil
+
+	return ret
+}
+
+// Listeners return the listeners currently bound by the server.
+func (s *Server) Listeners() []Listener {
+	return s.listeners.list()
+}
+
+// registerListener save the server listener and reference the server
+// in the pool used by the global Shutdown.
+func (s *Server) registerListener(l *listener) int {
+	poolMu.Lock()
+	poolOfServers[s] = struct{}{}
+	poolMu.Unlock()
+
+	return s.listeners.add(l)
+}
+
+// unregisterServer remove the server from the global pool.
+func (s *Server) unregisterServer() {
+	poolMu.Lock()
+	defer poolMu.Unlock()
+
+	delete(poolOfServers, s)
+}
@@ -0,0 +1,52 @@
+package webfmwk
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/burgesQ/gommon/webtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListeners(t *testing.T) {
+	var (
+		s1, uri1 = startSlowServer(t, 0)
+		s2, uri2 = startSlowServer(t, 0)
+	)
+
+	t.Cleanup(func() { require.Nil(t, s2.ShutdownAndWait()) })
+
+	t.Log("each server own its listeners")
+	{
+		l1, l2 := s1.Listeners(), s2.Listeners()
+		require.Len(t, l1, 1)
+		require.Len(t, l2, 1)
+		assert.NotEqual(t, l1[0].Addr, l2[0].Addr)
+		assert.False(t, l1[0].TLS || l1[0].HTTP2 || l1[0].Unix)
+	}
+
+	t.Log("shutting down a server doesn't stop the other one")
+	{
+		require.Nil(t, s1.ShutdownAndWait())
+		assert.Empty(t, s1.Listeners())
+		assert.Len(t, s2.Listeners(), 1)
+
+		webtest.RequestAndTestAPI(t, uri2+"/slow", func(t *testing.T, resp *http.Response) {
+			t.Helper()
+			webtest.StatusCode(t, http.StatusOK, resp)
+		})
+
+		_, e := http.Get(uri1 + "/slow") //nolint:noctx,bodyclose
+		assert.NotNil(t, e)
+	}
+}
+
+func TestGlobalShutdown(t *testing.T) {
+	s, _ := startSlowServer(t, 0)
+
+	require.Len(t, s.Listeners(), 1)
+	require.Nil(t, Shutdown())
+	s.WaitForStop()
+	assert.Empty(t, s.Listeners())
+}
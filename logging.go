@@ -0,0 +1,226 @@
+package webfmwk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LogServer name the server logger, see GetStructuredLogger.
+	LogServer = "server"
+	// LogAccess name the access logger, used by the slogging handler.
+	LogAccess = "access"
+	// LogTLS name the TLS material and handshake logger.
+	LogTLS = "tls"
+	// LogWorkers name the workers and jobs logger.
+	LogWorkers = "workers"
+
+	_loggerKey = "logger"
+)
+
+// ErrUnknownLogger is returned by SetLogLevel for an unknown logger name.
+var ErrUnknownLogger = errors.New("unknown logger")
+
+type (
+	// loggers hold the level of the named loggers, all derived from the
+	// server base logger.
+	loggers struct {
+		base     *slog.Logger
+		levels   map[string]*slog.LevelVar
+		explicit map[string]bool
+		mu       sync.RWMutex
+	}
+
+	// levelHandler filter the records of the wrapped handler using a
+	// dynamic level, unless forced.
+	levelHandler struct {
+		slog.Handler
+		level *slog.LevelVar
+		force bool
+	}
+)
+
+func newLoggers(base *slog.Logger) *loggers {
+	lg := &loggers{levels: make(map[string]*slog.LevelVar), explicit: make(map[string]bool)}
+
+	for _, name := range []string{LogServer, LogAccess, LogTLS, LogWorkers} {
+		lg.levels[name] = new(slog.LevelVar)
+	}
+
+	lg.setBase(base)
+
+	return lg
+}
+
+// setBase replace the base logger. The loggers level not explicitly set
+// default to the lowest level enabled by the base handler.
+func (lg *loggers) setBase(base *slog.Logger) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+
+	lg.base = base
+
+	def := baseLevel(base.Handler())
+
+	for name, lv := range lg.levels {
+		if !lg.explicit[name] {
+			lv.Set(def)
+		}
+	}
+}
+
+// level return the name logger level, created if needed. The lock must be held.
+func (lg *loggers) level(name string) *slog.LevelVar {
+	lv, ok := lg.levels[name]
+	if !ok {
+		lv = new(slog.LevelVar)
+		lv.Set(lg.levels[LogServer].Level())
+		lg.levels[name] = lv
+	}
+
+	return lv
+}
+
+// logger return the name logger. A forced logger log all the levels.
+func (lg *loggers) logger(name string, force bool) *slog.Logger {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+
+	l := slog.New(&levelHandler{Handler: lg.base.Handler(), level: lg.level(name), force: force})
+	if name != LogServer {
+		l = l.With(slog.String(_loggerKey, name))
+	}
+
+	return l
+}
+
+// baseLevel return the lowest level enabled by the h handler.
+func baseLevel(h slog.Handler) slog.Level {
+	for _, l := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn} {
+		if h.Enabled(context.Background(), l) {
+			return l
+		}
+	}
+
+	return slog.LevelError
+}
+
+// Enabled implement slog.Handler.
+func (h *levelHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return h.force || l >= h.level.Level()
+}
+
+// WithAttrs implement slog.Handler.
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level, force: h.force}
+}
+
+// WithGroup implement slog.Handler.
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level, force: h.force}
+}
+
+// Logger return the name logger, derived from the server structured logger
+// and filtered by its own level (see SetLogLevel). The loggers other than
+// LogServer add a `logger` attribute. Unknown names are registered with the
+// LogServer level.
+func (s *Server) Logger(name string) *slog.Logger { return s.loggers.logger(name, false) }
+
+// LogLevels return the level of the named loggers.
+func (s *Server) LogLevels() map[string]slog.Level {
+	s.loggers.mu.RLock()
+	defer s.loggers.mu.RUnlock()
+
+	ret := make(map[string]slog.Level, len(s.loggers.levels))
+	for name, lv := range s.loggers.levels {
+		ret[name] = lv.Level()
+	}
+
+	return ret
+}
+
+// SetLogLevel change at runtime the level of the name logger, see Logger.
+// ErrUnknownLogger is returned if the logger isn't registered.
+func (s *Server) SetLogLevel(name string, level slog.Level) error {
+	s.loggers.mu.Lock()
+	defer s.loggers.mu.Unlock()
+
+	lv, ok := s.loggers.levels[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownLogger, name)
+	}
+
+	lv.Set(level)
+	s.loggers.explicit[name] = true
+
+	return nil
+}
+
+// WithLogLevel set the initial level of the name logger. By default, the
+// loggers use the lowest level enabled by the structured logger handler.
+func WithLogLevel(name string, level slog.Level) Option {
+	return func(s *Server) {
+		s.loggers.mu.Lock()
+		s.loggers.level(name).Set(level)
+		s.loggers.explicit[name] = true
+		s.loggers.mu.Unlock()
+
+		s.slog.Debug("\t-- log level loaded", slog.String("logger", name), slog.String("level", level.String()))
+	}
+}
+
+// WithDebugHeader enable the per request debug logging: a request carrying
+// in the header header a valid token signed with key (see NewDebugToken) is
+// logged at all levels, whatever the loggers level.
+func WithDebugHeader(header string, key []byte) Option {
+	return func(s *Server) {
+		s.meta.debugHeader, s.meta.debugKey = header, key
+		s.slog.Debug("\t-- debug header loaded", slog.String("header", header))
+	}
+}
+
+// NewDebugToken return a token signed with key, valid for ttl, forcing the
+// debug logging of a request when passed via the WithDebugHeader header.
+func NewDebugToken(key []byte, ttl time.Duration) string {
+	exp := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	return exp + "." + signDebugToken(key, exp)
+}
+
+// verifyDebugToken return true if the token is signed with key and isn't
+// expired at now.
+func verifyDebugToken(key []byte, token string, now time.Time) bool {
+	exp, sig, ok := strings.Cut(token, ".")
+	if !ok || len(key) == 0 {
+		return false
+	}
+
+	ts, e := strconv.ParseInt(exp, 10, 64)
+	if e != nil || now.Unix() > ts {
+		return false
+	}
+
+	return hmac.Equal([]byte(sig), []byte(signDebugToken(key, exp)))
+}
+
+// signDebugToken return the hex encoded hmac of the exp expiration.
+func signDebugToken(key []byte, exp string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(exp))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// debugRequested return true if the request carry a valid debug token.
+func (s *Server) debugRequested(token []byte) bool {
+	return len(token) > 0 && verifyDebugToken(s.meta.debugKey, string(token), time.Now())
+}
@@ -0,0 +1,134 @@
+package webfmwk
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestLogLevels(t *testing.T) {
+	var buf bytes.Buffer
+
+	s, e := InitServer(
+		WithStructuredLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))),
+		WithLogLevel(LogTLS, slog.LevelError))
+	require.Nil(t, e)
+
+	levels := s.LogLevels()
+	assert.Equal(t, slog.LevelInfo, levels[LogServer])
+	assert.Equal(t, slog.LevelInfo, levels[LogAccess])
+	assert.Equal(t, slog.LevelError, levels[LogTLS])
+
+	s.Logger(LogTLS).Warn("filtered")
+	s.Logger(LogAccess).Info("logged")
+	assert.NotContains(t, buf.String(), "filtered")
+	assert.Contains(t, buf.String(), "logger=access")
+
+	require.Nil(t, s.SetLogLevel(LogAccess, slog.LevelWarn))
+	require.ErrorIs(t, s.SetLogLevel("nope", slog.LevelWarn), ErrUnknownLogger)
+
+	buf.Reset()
+	s.Logger(LogAccess).Info("filtered")
+	assert.Empty(t, buf.String())
+
+	// an explicit level survive a structured logger update
+	s.registerStructuredLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	assert.Equal(t, slog.LevelWarn, s.LogLevels()[LogAccess])
+	assert.Equal(t, slog.LevelDebug, s.LogLevels()[LogServer])
+}
+
+func TestDebugHeader(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		key = []byte("secret")
+	)
+
+	s, e := InitServer(
+		WithStructuredLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))),
+		WithDebugHeader("X-Debug", key))
+	require.Nil(t, e)
+
+	s.GET("/debug", func(c Context) error {
+		c.GetStructuredLogger().Debug("handler debug")
+		c.GetLogger(LogAccess).Debug("access debug")
+
+		return c.JSONNoContent()
+	})
+
+	tests := map[string]struct {
+		token  string
+		logged bool
+	}{
+		"valid":   {NewDebugToken(key, time.Minute), true},
+		"none":    {"", false},
+		"expired": {NewDebugToken(key, -time.Minute), false},
+		"forged":  {NewDebugToken([]byte("other"), time.Minute), false},
+		"garbage": {"nope", false},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+
+			fc := &fasthttp.RequestCtx{}
+			fc.Request.Header.SetMethod(http.MethodGet)
+			fc.Request.SetRequestURI("/debug")
+			fc.Request.Header.Set("X-Debug", test.token)
+
+			s.GetRouter().Handler(fc)
+			assert.Equal(t, http.StatusNoContent, fc.Response.StatusCode())
+
+			assert.Equal(t, test.logged, strings.Contains(buf.String(), "handler debug"))
+			assert.Equal(t, test.logged, strings.Contains(buf.String(), "access debug"))
+		})
+	}
+}
+
+func TestLoggersEndpoint(t *testing.T) {
+	s, e := InitServer(WithAdminAddress(Address{Addr: "127.0.0.1:0"}))
+	require.Nil(t, e)
+
+	var (
+		r      = s.GetAdminRouter()
+		levels map[string]slog.Level
+	)
+
+	put := func(t *testing.T, uri, body string) *fasthttp.RequestCtx {
+		t.Helper()
+
+		fc := &fasthttp.RequestCtx{}
+		fc.Request.Header.SetMethod(http.MethodPut)
+		fc.Request.SetRequestURI(uri)
+		fc.Request.SetBodyString(body)
+		r.Handler(fc)
+
+		return fc
+	}
+
+	fc := put(t, "/loggers/access", `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, fc.Response.StatusCode())
+	require.Nil(t, json.Unmarshal(fc.Response.Body(), &levels))
+	assert.Equal(t, slog.LevelDebug, levels[LogAccess])
+
+	assert.Equal(t, http.StatusNotFound, put(t, "/loggers/nope", `{"level":"debug"}`).Response.StatusCode())
+	assert.Equal(t, http.StatusUnprocessableEntity, put(t, "/loggers/tls", `{"level":"loud"}`).Response.StatusCode())
+
+	fc = &fasthttp.RequestCtx{}
+	fc.Request.Header.SetMethod(http.MethodGet)
+	fc.Request.SetRequestURI("/loggers")
+	r.Handler(fc)
+	assert.Equal(t, http.StatusOK, fc.Response.StatusCode())
+	require.Nil(t, json.Unmarshal(fc.Response.Body(), &levels))
+	assert.Equal(t, slog.LevelDebug, levels[LogAccess])
+	assert.Contains(t, levels, LogWorkers)
+}
@@ -0,0 +1,96 @@
+package webfmwk
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	// ConnObserver is notified of the connections life cycle of the server
+	// listeners. Register it via the WithConnObservers option.
+	ConnObserver interface {
+		// ConnState is called on each connection state change.
+		ConnState(l Listener, state fasthttp.ConnState)
+
+		// TLSHandshake is called once the handshake of a TLS connection
+		// completed. err is nil on success.
+		TLSHandshake(l Listener, err error)
+	}
+
+	// observedListener wrap a TLS listener to report the handshakes.
+	observedListener struct {
+		net.Listener
+		notify func(error)
+	}
+
+	// observedConn report the TLS handshake, run on the first read or
+	// explicitly via Handshake (ex: by the http2 server).
+	observedConn struct {
+		*tls.Conn
+		notify func(error)
+		once   sync.Once
+	}
+)
+
+// Accept implement net.Listener.
+func (ol *observedListener) Accept() (net.Conn, error) {
+	c, e := ol.Listener.Accept()
+	if e != nil {
+		return c, e
+	}
+
+	if tc, ok := c.(*tls.Conn); ok {
+		return &observedConn{Conn: tc, notify: ol.notify}, nil
+	}
+
+	return c, nil
+}
+
+// Handshake run the TLS handshake, reported once.
+func (oc *observedConn) Handshake() error {
+	oc.once.Do(func() { oc.notify(oc.Conn.Handshake()) })
+
+	// the handshake result is cached by the tls.Conn
+	return oc.Conn.Handshake()
+}
+
+// Read implement net.Conn.
+func (oc *observedConn) Read(b []byte) (int, error) {
+	if e := oc.Handshake(); e != nil {
+		return 0, e
+	}
+
+	return oc.Conn.Read(b)
+}
+
+// connState return the fasthttp.Server ConnState callback of the ln listener.
+func (s *Server) connState(ln *listener) func(net.Conn, fasthttp.ConnState) {
+	obs := s.meta.connObservers
+
+	return func(c net.Conn, state fasthttp.ConnState) {
+		s.conns.hook(c, state)
+		ln.conns.hook(c, state)
+
+		for i := range obs {
+			obs[i].ConnState(ln.Listener, state)
+		}
+	}
+}
+
+// observeTLS wrap the ln TLS listener so the handshakes are reported to the
+// connection observers, if any.
+func (s *Server) observeTLS(l Listener, ln net.Listener) net.Listener {
+	obs := s.meta.connObservers
+	if len(obs) == 0 {
+		return ln
+	}
+
+	return &observedListener{Listener: ln, notify: func(e error) {
+		for i := range obs {
+			obs[i].TLSHandshake(l, e)
+		}
+	}}
+}
@@ -0,0 +1,96 @@
+package webfmwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type handshakeObserver chan error
+
+func (ho handshakeObserver) ConnState(Listener, fasthttp.ConnState) {}
+
+func (ho handshakeObserver) TLSHandshake(_ Listener, e error) { ho <- e }
+
+func selfSigned(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, e)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, e := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.Nil(t, e)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestObserveTLS(t *testing.T) {
+	var (
+		obs    = make(handshakeObserver, 1)
+		s, e   = InitServer(WithConnObservers(obs))
+		ln, le = net.Listen("tcp4", "127.0.0.1:0")
+	)
+
+	require.Nil(t, e)
+	require.Nil(t, le)
+
+	defer ln.Close()
+
+	l := s.observeTLS(Listener{}, tls.NewListener(ln, &tls.Config{
+		Certificates: []tls.Certificate{selfSigned(t)},
+		MinVersion:   tls.VersionTLS12,
+	}))
+
+	go func() {
+		for {
+			c, e := l.Accept()
+			if e != nil {
+				return
+			}
+
+			go func() {
+				defer c.Close()
+
+				// the http2 server run the handshake explicitly, closing
+				// the connection on failure without reading it
+				if hc, ok := c.(interface{ Handshake() error }); ok && hc.Handshake() != nil {
+					return
+				}
+
+				_, _ = c.Read(make([]byte, 1))
+			}()
+		}
+	}()
+
+	t.Log("successful handshakes are reported")
+	{
+		c, e := tls.Dial("tcp4", ln.Addr().String(), &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+		require.Nil(t, e)
+		assert.Nil(t, <-obs)
+		_ = c.Close()
+	}
+
+	t.Log("failed handshakes are reported")
+	{
+		_, e := tls.Dial("tcp4", ln.Addr().String(), &tls.Config{MinVersion: tls.VersionTLS12})
+		require.NotNil(t, e)
+		assert.NotNil(t, <-obs)
+	}
+}
@@ -0,0 +1,51 @@
+package webfmwk
+
+import (
+	"net/http"
+
+	"github.com/burgesQ/webfmwk/v6/openapi"
+)
+
+// OpenAPI generate the OpenAPI document of the registered routes.
+// The path params are extracted from the route path, the request, response
+// and query params schema from the Route Request, Response and Query values.
+// ANY routes are not documented.
+func (s *Server) OpenAPI() *openapi.Document {
+	b := openapi.NewBuilder(s.meta.openapiInfo)
+
+	for prefix := range s.meta.routes {
+		routes := s.meta.routes[prefix]
+
+		for i := range routes {
+			route := routes[i]
+
+			if route.Verbe == ANY {
+				continue
+			}
+
+			b.Add(routeToOperation(prefix, route))
+		}
+	}
+
+	return b.Document()
+}
+
+func routeToOperation(prefix string, route Route) openapi.OperationSpec {
+	op := openapi.OperationSpec{
+		Method:      route.Verbe,
+		Path:        prefix + route.Path,
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: route.Name,
+		Tags:        route.Tags,
+		Request:     route.Request,
+		Query:       route.Query,
+		Responses:   map[int]interface{}{routeStatus(route): route.Response},
+	}
+
+	if route.Request != nil || route.Query != nil {
+		op.Responses[http.StatusUnprocessableEntity] = ValidationError{}
+	}
+
+	return op
+}
@@ -0,0 +1,196 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const _jsonContentType = "application/json"
+
+type (
+	// Builder generate an OpenAPI Document from a set of OperationSpec.
+	Builder struct {
+		doc   *Document
+		names map[reflect.Type]string
+	}
+
+	// OperationSpec hold the data required to document an API operation.
+	OperationSpec struct {
+		// Responses hold a value of the response type per status code.
+		// A nil value document a response without payload.
+		Responses map[int]interface{}
+
+		// Request hold a value of the request payload type, if any.
+		Request interface{}
+
+		// Query hold a value of the query params type, if any.
+		// The fields are mapped to query parameters using the `schema` tag.
+		Query interface{}
+
+		// Path hold the route path, using the {name} syntax for the url params.
+		Path string
+
+		// Method hold the http verb.
+		Method string
+
+		Summary     string
+		Description string
+		OperationID string
+		Tags        []string
+	}
+)
+
+// NewBuilder return a Builder for the info API.
+func NewBuilder(info Info, servers ...Server) *Builder {
+	return &Builder{doc: &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Servers:    servers,
+		Paths:      map[string]*PathItem{},
+		Components: &Components{Schemas: map[string]*Schema{}},
+	}, names: map[reflect.Type]string{}}
+}
+
+// Document return the generated document.
+func (b *Builder) Document() *Document {
+	return b.doc
+}
+
+// Add document the op operation.
+func (b *Builder) Add(op OperationSpec) *Builder {
+	path, params := ParsePath(op.Path)
+
+	o := &Operation{
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.OperationID,
+		Tags:        op.Tags,
+		Parameters:  params,
+		Responses:   map[string]*Response{},
+	}
+
+	if op.Query != nil {
+		o.Parameters = append(o.Parameters, b.queryParams(reflect.TypeOf(op.Query))...)
+	}
+
+	if op.Request != nil {
+		o.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				_jsonContentType: {Schema: b.schemaOf(reflect.TypeOf(op.Request))},
+			},
+		}
+	}
+
+	for code, v := range op.Responses {
+		r := &Response{Description: http.StatusText(code)}
+
+		if v != nil {
+			r.Content = map[string]MediaType{
+				_jsonContentType: {Schema: b.schemaOf(reflect.TypeOf(v))},
+			}
+		}
+
+		o.Responses[strconv.Itoa(code)] = r
+	}
+
+	if len(o.Responses) == 0 {
+		o.Responses["default"] = &Response{Description: "default response"}
+	}
+
+	item, ok := b.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		b.doc.Paths[path] = item
+	}
+
+	(*item)[strings.ToLower(op.Method)] = o
+
+	return b
+}
+
+// queryParams return the query parameters of the t struct type.
+func (b *Builder) queryParams(t reflect.Type) []Parameter {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var params []Parameter
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name, skip := fieldName(f, "schema")
+		if skip {
+			continue
+		}
+
+		s := b.schemaOf(f.Type)
+		p := Parameter{Name: name, In: "query", Schema: s, Required: applyValidate(s, f)}
+
+		if d := f.Tag.Get("description"); d != "" {
+			p.Description = d
+		}
+
+		params = append(params, p)
+	}
+
+	return params
+}
+
+// ParsePath convert a fasthttp/router path to an OpenAPI path template and
+// return the matching path parameters. Supported segments are {name},
+// {name?}, {name:regex} and {name:*}.
+func ParsePath(path string) (string, []Parameter) {
+	var (
+		out    strings.Builder
+		params []Parameter
+	)
+
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			out.WriteString(path)
+
+			break
+		}
+
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			out.WriteString(path)
+
+			break
+		}
+
+		end += start
+
+		var (
+			raw           = path[start+1 : end]
+			name, re, has = strings.Cut(raw, ":")
+			s             = &Schema{Type: "string"}
+		)
+
+		name = strings.TrimSuffix(name, "?")
+
+		if has && re != "*" {
+			s.Pattern = "^" + re + "$"
+		}
+
+		out.WriteString(path[:start] + "{" + name + "}")
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: s})
+
+		path = path[end+1:]
+	}
+
+	return out.String(), params
+}
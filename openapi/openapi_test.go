@@ -0,0 +1,159 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	user struct {
+		Created time.Time         `json:"created"`
+		Tags    []string          `json:"tags"       validate:"max=5"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Manager *user             `json:"manager,omitempty"`
+		Name    string            `json:"first_name" validate:"required,alpha,min=2,max=32" example:"jean"`
+		Email   string            `json:"email"      validate:"required,email"`
+		Role    string            `json:"role"       validate:"oneof=admin user"`
+		Age     int               `json:"age"        validate:"gte=0,lt=150"`
+		secret  string
+	}
+
+	listQuery struct {
+		Page   *int   `schema:"page"   validate:"omitempty,min=1"`
+		Filter string `schema:"filter" validate:"required"`
+		Ignore string `schema:"-"`
+	}
+)
+
+func TestParsePath(t *testing.T) {
+	tests := map[string]struct {
+		path   string
+		want   string
+		params []string
+	}{
+		"static":    {"/users", "/users", nil},
+		"param":     {"/users/{id}", "/users/{id}", []string{"id"}},
+		"multiple":  {"/users/{id}/posts/{post}", "/users/{id}/posts/{post}", []string{"id", "post"}},
+		"regex":     {"/users/{id:[0-9]+}", "/users/{id}", []string{"id"}},
+		"optional":  {"/users/{id?}", "/users/{id}", []string{"id"}},
+		"catch all": {"/static/{filepath:*}", "/static/{filepath}", []string{"filepath"}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, params := ParsePath(test.path)
+			assert.Equal(t, test.want, path)
+			require.Len(t, params, len(test.params))
+
+			for i := range params {
+				assert.Equal(t, test.params[i], params[i].Name)
+				assert.Equal(t, "path", params[i].In)
+				assert.True(t, params[i].Required)
+			}
+		})
+	}
+
+	_, params := ParsePath("/users/{id:[0-9]+}")
+	assert.Equal(t, "^[0-9]+$", params[0].Schema.Pattern)
+}
+
+func TestBuilder(t *testing.T) {
+	doc := NewBuilder(Info{Title: "test", Version: "1.0.0"}).
+		Add(OperationSpec{
+			Method: "POST", Path: "/users/{id}", Summary: "create a user",
+			Request:   user{},
+			Responses: map[int]interface{}{201: user{}, 422: nil},
+		}).
+		Add(OperationSpec{
+			Method: "GET", Path: "/users",
+			Query:     listQuery{},
+			Responses: map[int]interface{}{200: []user{}},
+		}).
+		Document()
+
+	require.Equal(t, Version, doc.OpenAPI)
+	require.Contains(t, doc.Paths, "/users/{id}")
+	require.Contains(t, doc.Paths, "/users")
+
+	t.Log("request and response schemas are referenced")
+	{
+		post := (*doc.Paths["/users/{id}"])["post"]
+		require.NotNil(t, post)
+		assert.Equal(t, "create a user", post.Summary)
+		assert.Equal(t, "#/components/schemas/user", post.RequestBody.Content["application/json"].Schema.Ref)
+		assert.Equal(t, "#/components/schemas/user", post.Responses["201"].Content["application/json"].Schema.Ref)
+		assert.Nil(t, post.Responses["422"].Content)
+		assert.Equal(t, "id", post.Parameters[0].Name)
+
+		get := (*doc.Paths["/users"])["get"]
+		assert.Equal(t, "array", get.Responses["200"].Content["application/json"].Schema.Type)
+	}
+
+	t.Log("validate tags are mapped to constraints")
+	{
+		s := doc.Components.Schemas["user"]
+		require.NotNil(t, s)
+		assert.ElementsMatch(t, []string{"first_name", "email"}, s.Required)
+		assert.NotContains(t, s.Properties, "secret")
+
+		name := s.Properties["first_name"]
+		assert.Equal(t, 2, *name.MinLength)
+		assert.Equal(t, 32, *name.MaxLength)
+		assert.Equal(t, "^[a-zA-Z]+$", name.Pattern)
+		assert.Equal(t, "jean", name.Example)
+
+		assert.Equal(t, "email", s.Properties["email"].Format)
+		assert.Equal(t, []interface{}{"admin", "user"}, s.Properties["role"].Enum)
+		assert.Equal(t, 0.0, *s.Properties["age"].Minimum)
+		assert.Equal(t, 150.0, *s.Properties["age"].ExclusiveMaximum)
+		assert.Equal(t, 5, *s.Properties["tags"].MaxItems)
+		assert.Equal(t, "date-time", s.Properties["created"].Format)
+		assert.Equal(t, "#/components/schemas/user", s.Properties["manager"].Ref)
+		assert.Equal(t, "string", s.Properties["labels"].AdditionalProperties.Type)
+	}
+
+	t.Log("schema tags are mapped to query params")
+	{
+		params := (*doc.Paths["/users"])["get"].Parameters
+		require.Len(t, params, 2)
+		assert.Equal(t, "page", params[0].Name)
+		assert.Equal(t, "query", params[0].In)
+		assert.False(t, params[0].Required)
+		assert.Equal(t, 1.0, *params[0].Schema.Minimum)
+		assert.Equal(t, "filter", params[1].Name)
+		assert.True(t, params[1].Required)
+	}
+
+	_, e := json.Marshal(doc)
+	require.Nil(t, e)
+}
+
+func TestSchemaNameCollision(t *testing.T) {
+	pkgUser := user{}
+
+	// same name as the package level user type
+	type user struct {
+		Login string `json:"login"`
+	}
+
+	doc := NewBuilder(Info{Title: "test", Version: "1.0.0"}).
+		Add(OperationSpec{Method: "GET", Path: "/a", Responses: map[int]interface{}{200: user{}}}).
+		Add(OperationSpec{Method: "GET", Path: "/b", Responses: map[int]interface{}{200: &user{}}}).
+		Add(OperationSpec{Method: "PUT", Path: "/a", Request: pkgUser}).
+		Document()
+
+	t.Log("the same type is registered once")
+	require.Contains(t, doc.Components.Schemas, "user")
+	assert.Contains(t, doc.Components.Schemas["user"].Properties, "login")
+
+	t.Log("a type sharing the name of a registered one is suffixed")
+	require.Contains(t, doc.Components.Schemas, "user_2")
+	assert.Contains(t, doc.Components.Schemas["user_2"].Properties, "first_name")
+	assert.Equal(t, "#/components/schemas/user_2",
+		(*doc.Paths["/a"])["put"].RequestBody.Content["application/json"].Schema.Ref)
+	assert.Len(t, doc.Components.Schemas, 2)
+}
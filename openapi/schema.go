@@ -0,0 +1,337 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const _refPrefix = "#/components/schemas/"
+
+var (
+	_timeType       = reflect.TypeOf(time.Time{})
+	_durationType   = reflect.TypeOf(time.Duration(0))
+	_rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaOf return the schema of the t type. Named structs are registered in
+// the components and referenced.
+func (b *Builder) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case _timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case _durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	case _rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+
+	case reflect.String:
+		return &Schema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+
+		name, ok := b.names[t]
+		if !ok {
+			// reserve the name first to support recursive types
+			name = b.componentName(t)
+			b.names[t] = name
+			b.doc.Components.Schemas[name] = &Schema{}
+			*b.doc.Components.Schemas[name] = *b.structSchema(t)
+		}
+
+		return &Schema{Ref: _refPrefix + name}
+
+	default:
+		// interface, func, chan ...: any value
+		return &Schema{}
+	}
+}
+
+// structSchema return the object schema of the t struct type.
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name, skip := fieldName(f, "json")
+		if skip {
+			continue
+		}
+
+		// embedded struct without json name: promote its fields
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				emb := b.structSchema(ft)
+				for k, v := range emb.Properties {
+					s.Properties[k] = v
+				}
+
+				s.Required = append(s.Required, emb.Required...)
+
+				continue
+			}
+		}
+
+		fs := b.schemaOf(f.Type)
+
+		if required := applyValidate(fs, f); required {
+			s.Required = append(s.Required, name)
+		}
+
+		applyDoc(fs, f)
+		s.Properties[name] = fs
+	}
+
+	return s
+}
+
+// componentName return an unused component name for the t type. Types of
+// different packages sharing a name are suffixed, ex: user, user_2.
+func (b *Builder) componentName(t reflect.Type) string {
+	name := schemaName(t)
+
+	for i := 2; ; i++ {
+		if _, used := b.doc.Components.Schemas[name]; !used {
+			return name
+		}
+
+		name = schemaName(t) + "_" + strconv.Itoa(i)
+	}
+}
+
+// schemaName return the component name of a named type.
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+
+	// generic instances: Page[pkg.Item] -> Page_Item
+	if i := strings.IndexByte(name, '['); i > 0 {
+		args := name[i+1 : len(name)-1]
+		if j := strings.LastIndexByte(args, '.'); j >= 0 {
+			args = args[j+1:]
+		}
+
+		name = name[:i] + "_" + args
+	}
+
+	return name
+}
+
+// fieldName return the name of the f field using the tag struct tag.
+func fieldName(f reflect.StructField, tag string) (string, bool) {
+	name := strings.SplitN(f.Tag.Get(tag), ",", 2)[0] //nolint: gomnd
+
+	switch name {
+	case "-":
+		return "", true
+	case "":
+		return f.Name, false
+	}
+
+	return name, false
+}
+
+// applyDoc load the description and example struct tags.
+func applyDoc(s *Schema, f reflect.StructField) {
+	if d := f.Tag.Get("description"); d != "" {
+		s.Description = d
+	}
+
+	if ex := f.Tag.Get("example"); ex != "" {
+		s.Example = ex
+	}
+}
+
+// applyValidate map the go-playground/validator tags of the f field to
+// schema constraints. It return true if the field is required.
+func applyValidate(s *Schema, f reflect.StructField) (required bool) {
+	tag := f.Tag.Get("validate")
+	if tag == "" || tag == "-" {
+		return false
+	}
+
+	kind := f.Type.Kind()
+	if kind == reflect.Pointer {
+		kind = f.Type.Elem().Kind()
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		// stop at the dive: following rules apply to the items
+		if rule == "dive" {
+			break
+		}
+
+		key, val, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "required":
+			required = true
+
+		case "min", "gte":
+			setLowerBound(s, kind, val, false)
+
+		case "max", "lte":
+			setUpperBound(s, kind, val, false)
+
+		case "gt":
+			setLowerBound(s, kind, val, true)
+
+		case "lt":
+			setUpperBound(s, kind, val, true)
+
+		case "len":
+			setLowerBound(s, kind, val, false)
+			setUpperBound(s, kind, val, false)
+
+		case "oneof":
+			for _, v := range strings.Fields(val) {
+				s.Enum = append(s.Enum, enumValue(kind, v))
+			}
+
+		case "email":
+			s.Format = "email"
+		case "url", "uri", "http_url":
+			s.Format = "uri"
+		case "uuid", "uuid4", "uuid_rfc4122", "uuid4_rfc4122":
+			s.Format = "uuid"
+		case "ipv4":
+			s.Format = "ipv4"
+		case "ipv6":
+			s.Format = "ipv6"
+		case "hostname", "hostname_rfc1123":
+			s.Format = "hostname"
+		case "datetime":
+			s.Format = "date-time"
+		case "alpha":
+			s.Pattern = "^[a-zA-Z]+$"
+		case "alphanum":
+			s.Pattern = "^[a-zA-Z0-9]+$"
+		case "numeric":
+			s.Pattern = "^[-+]?[0-9]+(?:\\.[0-9]+)?$"
+		}
+	}
+
+	return required
+}
+
+func parseFloat(val string) *float64 {
+	f, e := strconv.ParseFloat(val, 64)
+	if e != nil {
+		return nil
+	}
+
+	return &f
+}
+
+func parseInt(val string) *int {
+	i, e := strconv.Atoi(val)
+	if e != nil {
+		return nil
+	}
+
+	return &i
+}
+
+func setLowerBound(s *Schema, kind reflect.Kind, val string, exclusive bool) {
+	switch kind {
+	case reflect.String:
+		s.MinLength = parseInt(val)
+		if exclusive && s.MinLength != nil {
+			*s.MinLength++
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		s.MinItems = parseInt(val)
+		if exclusive && s.MinItems != nil {
+			*s.MinItems++
+		}
+	default:
+		if exclusive {
+			s.ExclusiveMinimum = parseFloat(val)
+		} else {
+			s.Minimum = parseFloat(val)
+		}
+	}
+}
+
+func setUpperBound(s *Schema, kind reflect.Kind, val string, exclusive bool) {
+	switch kind {
+	case reflect.String:
+		s.MaxLength = parseInt(val)
+		if exclusive && s.MaxLength != nil {
+			*s.MaxLength--
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		s.MaxItems = parseInt(val)
+		if exclusive && s.MaxItems != nil {
+			*s.MaxItems--
+		}
+	default:
+		if exclusive {
+			s.ExclusiveMaximum = parseFloat(val)
+		} else {
+			s.Maximum = parseFloat(val)
+		}
+	}
+}
+
+func enumValue(kind reflect.Kind, v string) interface{} {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, e := strconv.ParseInt(v, 10, 64); e == nil {
+			return i
+		}
+	case reflect.Float32, reflect.Float64:
+		if f := parseFloat(v); f != nil {
+			return *f
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,98 @@
+// Package openapi implement a minimal OpenAPI 3.1 document model and a
+// generator building schemas from Go types.
+package openapi
+
+// Version is the OpenAPI specification version of the generated documents.
+const Version = "3.1.0"
+
+type (
+	// Document is the root object of an OpenAPI document.
+	Document struct {
+		Components *Components          `json:"components,omitempty"`
+		Paths      map[string]*PathItem `json:"paths"`
+		OpenAPI    string               `json:"openapi"`
+		Info       Info                 `json:"info"`
+		Servers    []Server             `json:"servers,omitempty"`
+	}
+
+	// Info hold the API metadata.
+	Info struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// Server hold an API server URL.
+	Server struct {
+		URL         string `json:"url"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// Components hold the reusable schemas.
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas,omitempty"`
+	}
+
+	// PathItem hold the operations available on a path, per lower case http verb.
+	PathItem map[string]*Operation
+
+	// Operation describe a single API operation on a path.
+	Operation struct {
+		RequestBody *RequestBody         `json:"requestBody,omitempty"`
+		Responses   map[string]*Response `json:"responses"`
+		Summary     string               `json:"summary,omitempty"`
+		Description string               `json:"description,omitempty"`
+		OperationID string               `json:"operationId,omitempty"`
+		Tags        []string             `json:"tags,omitempty"`
+		Parameters  []Parameter          `json:"parameters,omitempty"`
+	}
+
+	// Parameter describe a single operation parameter.
+	Parameter struct {
+		Schema      *Schema `json:"schema,omitempty"`
+		Name        string  `json:"name"`
+		In          string  `json:"in"`
+		Description string  `json:"description,omitempty"`
+		Required    bool    `json:"required,omitempty"`
+	}
+
+	// RequestBody describe an operation request payload.
+	RequestBody struct {
+		Content  map[string]MediaType `json:"content"`
+		Required bool                 `json:"required,omitempty"`
+	}
+
+	// Response describe a single operation response.
+	Response struct {
+		Content     map[string]MediaType `json:"content,omitempty"`
+		Description string               `json:"description"`
+	}
+
+	// MediaType hold the schema of a payload.
+	MediaType struct {
+		Schema *Schema `json:"schema,omitempty"`
+	}
+
+	// Schema is a JSON schema (draft 2020-12) subset.
+	Schema struct {
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+		Minimum              *float64           `json:"minimum,omitempty"`
+		Maximum              *float64           `json:"maximum,omitempty"`
+		ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+		MinLength            *int               `json:"minLength,omitempty"`
+		MaxLength            *int               `json:"maxLength,omitempty"`
+		MinItems             *int               `json:"minItems,omitempty"`
+		MaxItems             *int               `json:"maxItems,omitempty"`
+		Ref                  string             `json:"$ref,omitempty"`
+		Type                 string             `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Pattern              string             `json:"pattern,omitempty"`
+		Description          string             `json:"description,omitempty"`
+		Example              interface{}        `json:"example,omitempty"`
+		Enum                 []interface{}      `json:"enum,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+	}
+)
@@ -0,0 +1,54 @@
+package webfmwk
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/burgesQ/webfmwk/v6/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI(t *testing.T) {
+	s, e := InitServer(
+		SetPrefix("/api"),
+		WithOpenAPI("/doc/openapi.json", openapi.Info{Title: "test", Version: "1.0.0"}))
+	require.Nil(t, e)
+
+	s.AddRoutes(Route{
+		Verbe: POST, Path: "/users/{id}", Name: "createUser", Tags: []string{"users"},
+		Handler: _emptyController, Request: userForm{}, Response: userForm{},
+	}, Route{
+		Verbe: GET, Path: "/users", Handler: _emptyController, Query: queryParam{},
+	}, Route{
+		Verbe: ANY, Path: "/any", Handler: _emptyController,
+	})
+
+	s.Group("/v2").GET("/users/{id}", _emptyController)
+
+	fc := requestRouter(t, s, GET, "/api/doc/openapi.json")
+	require.Equal(t, http.StatusOK, fc.Response.StatusCode())
+
+	var doc openapi.Document
+
+	require.Nil(t, json.Unmarshal(fc.Response.Body(), &doc))
+	assert.Equal(t, openapi.Version, doc.OpenAPI)
+	assert.Equal(t, "test", doc.Info.Title)
+	assert.Len(t, doc.Paths, 3)
+	assert.NotContains(t, doc.Paths, "/api/any")
+	assert.Contains(t, doc.Paths, "/api/v2/users/{id}")
+
+	post := (*doc.Paths["/api/users/{id}"])["post"]
+	require.NotNil(t, post)
+	assert.Equal(t, "createUser", post.OperationID)
+	assert.Equal(t, []string{"users"}, post.Tags)
+	assert.Equal(t, "id", post.Parameters[0].Name)
+	assert.Contains(t, post.Responses, "422")
+	assert.Equal(t, []string{"first_name", "last_name"}, doc.Components.Schemas["userForm"].Required)
+
+	get := (*doc.Paths["/api/users"])["get"]
+	require.NotNil(t, get)
+	require.Len(t, get.Parameters, 2)
+	assert.Equal(t, "some", get.Parameters[0].Name)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/burgesQ/webfmwk/v6/openapi"
 	"github.com/valyala/fasthttp"
 )
 
@@ -37,20 +39,40 @@ type (
 		socketIOHandlerFunc http.HandlerFunc
 		baseServer          *fasthttp.Server
 		routes              RoutesPerPrefix
+		codecs              codecs
 		prefix              string
+		shutdownTimeout     time.Duration
+		tlsReload           time.Duration
+		upgradeSignal       os.Signal
 		pprofPath           string
+		openapiPath         string
+		openapiInfo         openapi.Info
 		socketIOPath        string
 		docHandlers         []DocHandler
+		adminHandlers       []DocHandler
+		admin               *Address
+		debugHeader         string
+		debugKey            []byte
+		connObservers       []ConnObserver
 		handlers            []Handler
+		ctrlcOnce           sync.Once
+		sighupOnce          sync.Once
+		upgradeOnce         sync.Once
 		cors                bool
 		socketIOHF          bool
 		socketIOH           bool
 		pprof               bool
+		openapi             bool
+		problem             bool
 		enableKeepAlive     bool
-		ctrlcStarted        bool
 		checkIsUp           bool
+		health              bool
+		jobs                bool
 		ctrlc               bool
+		tlsSignal           bool
 		http2               bool
+		systemd             bool
+		sdNotify            bool
 	}
 )
 
@@ -89,12 +111,19 @@ func InitServer(opts ...Option) (*Server, error) {
 			ctx:      ctx,
 			cancel:   cancel,
 			wg:       &wg,
-			slog:     slog.Default(),
 			isReady:  make(chan bool),
+			conns:    newConnTracker(),
+			health:   newHealthRegistry(),
+			ready:    newReadiness(),
+			workers:  workers{all: make(map[string]*worker)},
+			jobs:     scheduler{jobs: make(map[string]*job)},
+			loggers:  newLoggers(slog.Default()),
 			meta:     getDefaultMeta(),
 		}
 	)
 
+	s.slog = s.loggers.logger(LogServer, false)
+
 	useOptions(s, opts...)
 
 	return s, e
@@ -125,8 +154,8 @@ func WithCtrlC() Option {
 	}
 }
 
-// CheckIsUp expose a `/ping` endpoint and try to poll to check the server healt
-// when it's started.
+// CheckIsUp expose a `/ping` endpoint, replying with a 503 status once the
+// server start its shutdown sequence.
 func CheckIsUp() Option {
 	return func(s *Server) {
 		s.EnableCheckIsUp()
@@ -152,6 +181,9 @@ func SetPrefix(prefix string) Option {
 
 // WithDocHandlers allow to register custom DocHandler struct (ex: swaggo, redoc).
 // If use with SetPrefix, register WithDocHandler after the SetPrefix one.
+// The DocHandler are served by the public listeners, even once
+// WithAdminAddress is set: register the operational ones (ex: metrics) via
+// WithAdminHandlers instead.
 // Example:
 //
 //	package main
@@ -171,6 +203,18 @@ func WithDocHandlers(handler ...DocHandler) Option {
 	}
 }
 
+// WithConnObservers register ConnObserver notified of the connections
+// life cycle (state changes and TLS handshakes) of every listener.
+//
+//	m := metrics.New()
+//	s, _ := webfmwk.InitServer(webfmwk.WithConnObservers(m))
+func WithConnObservers(obs ...ConnObserver) Option {
+	return func(s *Server) {
+		s.meta.connObservers = append(s.meta.connObservers, obs...)
+		s.slog.Debug("\t-- connection observers loaded")
+	}
+}
+
 // WithHandlers allow to register a list of webfmwk.Handler
 // Handler signature is the webfmwk.HandlerFunc one (func(c Context)).
 // To register a custom context, simply do it in the toppest handler.
@@ -201,6 +245,27 @@ func WithHandlers(h ...Handler) Option {
 	}
 }
 
+// WithCodecs register extra Codec used to decode the payloads and to Render
+// the responses. A codec replace the registered one handling the same content
+// type. JSON, XML, MessagePack, CBOR and YAML are registered by default.
+func WithCodecs(c ...Codec) Option {
+	return func(s *Server) {
+		s.meta.codecs = s.meta.codecs.register(c...)
+		s.slog.Debug("\t-- codecs loaded")
+	}
+}
+
+// WithProblemDetails answer the errors as RFC 9457 problem details
+// (application/problem+json) instead of the default json error payload.
+// It apply to HandleError, the 404 / 405 responses, the validation failures
+// and the recover handler.
+func WithProblemDetails() Option {
+	return func(s *Server) {
+		s.meta.problem = true
+		s.slog.Debug("\t-- problem details enabled")
+	}
+}
+
 // SetReadTimeout is a timing constraint on the client http request imposed by
 // the server from the moment
 // of initial connection up to the time the entire request body has been read.
@@ -234,6 +299,43 @@ func SetIDLETimeout(val time.Duration) Option {
 	}
 }
 
+// SetShutdownTimeout bound the time Shutdown wait for the in-flight requests
+// to complete before force closing the remaining connections.
+// A zero value (the default) wait indefinitely.
+func SetShutdownTimeout(val time.Duration) Option {
+	return func(s *Server) {
+		s.meta.shutdownTimeout = val
+		s.slog.Debug("\t-- shutdown timeout loaded")
+	}
+}
+
+// WithTLSReload enable the TLS certificates hot reload: the cert, key and ca
+// files are polled every interval and reloaded once modified. A SIGHUP also
+// trigger a reload, as with WithTLSReloadSignal.
+// Use Server.ReloadTLS to trigger a reload from the code.
+func WithTLSReload(interval time.Duration) Option {
+	return func(s *Server) {
+		if interval <= 0 {
+			s.slog.Warn("\t-- invalid tls reload interval, skipping", slog.Duration("interval", interval))
+
+			return
+		}
+
+		s.meta.tlsReload, s.meta.tlsSignal = interval, true
+		s.slog.Debug("\t-- tls reload enabled")
+	}
+}
+
+// WithTLSReloadSignal reload the TLS certificates on SIGHUP, without polling
+// the files. The SIGHUP no longer stop the server (see WithCtrlC).
+// Use Server.ReloadTLS to trigger a reload from the code.
+func WithTLSReloadSignal() Option {
+	return func(s *Server) {
+		s.meta.tlsSignal = true
+		s.slog.Debug("\t-- tls reload on SIGHUP enabled")
+	}
+}
+
 // EnableKeepAlive disable the server keep alive functions.
 func EnableKeepAlive() Option {
 	return func(s *Server) {
@@ -242,6 +344,22 @@ func EnableKeepAlive() Option {
 	}
 }
 
+// WithOpenAPI expose an OpenAPI 3.1 document generated from the registered
+// routes on the path endpoint. If a prefix is setup, the path will be prefixed.
+// Point the redoc DocURI to the same path to render it. The document is
+// served by the public listeners, even once WithAdminAddress is set.
+//
+//	s, _ := webfmwk.InitServer(
+//		webfmwk.SetPrefix("/api"),
+//		webfmwk.WithOpenAPI("/doc/openapi.json", openapi.Info{Title: "api", Version: "1.0.0"}),
+//		webfmwk.WithDocHandlers(redoc.GetHandler(redoc.DocURI("/api/doc/openapi.json"))))
+func WithOpenAPI(path string, info openapi.Info) Option {
+	return func(s *Server) {
+		s.meta.openapi, s.meta.openapiPath, s.meta.openapiInfo = true, path, info
+		s.slog.Debug("\t-- openapi endpoint enabled")
+	}
+}
+
 // EnablePprof enable the pprof endpoints.
 func EnablePprof(path ...string) Option {
 	return func(s *Server) {
@@ -262,6 +380,35 @@ func MaxRequestBodySize(size int) Option {
 	}
 }
 
+// SetConcurrency bound the number of connections served concurrently by each
+// listener. The excess connections are answered a 503. Default to
+// fasthttp.DefaultConcurrency. See the handler/concurrency package to bound
+// the in-flight requests instead.
+func SetConcurrency(n int) Option {
+	return func(s *Server) {
+		s.meta.baseServer.Concurrency = n
+		s.slog.Debug("\t-- concurrency set", "value", n)
+	}
+}
+
+// SetMaxConnsPerIP bound the number of concurrent connections per client IP.
+// Zero (the default) mean unlimited.
+func SetMaxConnsPerIP(n int) Option {
+	return func(s *Server) {
+		s.meta.baseServer.MaxConnsPerIP = n
+		s.slog.Debug("\t-- max connections per ip set", "value", n)
+	}
+}
+
+// SetMaxRequestsPerConn bound the number of requests served per keep-alive
+// connection, closed once reached. Zero (the default) mean unlimited.
+func SetMaxRequestsPerConn(n int) Option {
+	return func(s *Server) {
+		s.meta.baseServer.MaxRequestsPerConn = n
+		s.slog.Debug("\t-- max requests per connection set", "value", n)
+	}
+}
+
 const (
 	ReadTimeout  = 20
 	WriteTimeout = 20
@@ -292,6 +439,7 @@ func getDefaultMeta() serverMeta {
 			MaxRequestBodySize: fasthttp.DefaultMaxRequestBodySize,
 		},
 		routes:    make(RoutesPerPrefix),
+		codecs:    defaultCodecs(),
 		pprofPath: "/debug/pprof/{profile:*}",
 	}
 }
@@ -302,12 +450,16 @@ func (m *serverMeta) toServer(addr string) *fasthttp.Server {
 		WriteTimeout:                  m.baseServer.WriteTimeout,
 		IdleTimeout:                   m.baseServer.IdleTimeout,
 		MaxRequestBodySize:            m.baseServer.MaxRequestBodySize,
+		Concurrency:                   m.baseServer.Concurrency,
+		MaxConnsPerIP:                 m.baseServer.MaxConnsPerIP,
+		MaxRequestsPerConn:            m.baseServer.MaxRequestsPerConn,
 		Name:                          "webfmwk " + addr,
 		DisableKeepalive:              !m.enableKeepAlive,
 		DisableHeaderNamesNormalizing: true,
 		ReduceMemoryUsage:             true,
 		LogAllErrors:                  true,
 		CloseOnShutdown:               true,
+		ErrorHandler:                  readErrorHandler,
 	}
 
 	// if m.http2 {
@@ -37,6 +37,9 @@ func TestServerNewInit(t *testing.T) {
 			EnablePprof("/some/path"),
 			WithHandlers(_emptyHandler),
 			MaxRequestBodySize(42),
+			SetConcurrency(64),
+			SetMaxConnsPerIP(8),
+			SetMaxRequestsPerConn(100),
 			WithSocketHandler("/sock_1", ws{}),
 			WithSocketHandlerFunc("/sock_1", func(http.ResponseWriter, *http.Request) {}),
 		)
@@ -69,4 +72,7 @@ func TestServerNewInit(t *testing.T) {
 	requirer.Equal(testT, ht.ReadTimeout)
 	requirer.Equal(testT, ht.WriteTimeout)
 	requirer.Equal(testT, ht.IdleTimeout)
+	requirer.Equal(64, ht.Concurrency)
+	requirer.Equal(8, ht.MaxConnsPerIP)
+	requirer.Equal(100, ht.MaxRequestsPerConn)
 }
@@ -0,0 +1,35 @@
+//go:build linux
+
+package webfmwk
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerCredentials return the SO_PEERCRED credentials of the c unix connection.
+func peerCredentials(c net.Conn) (PeerCredentials, bool) {
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return PeerCredentials{}, false
+	}
+
+	rc, e := uc.SyscallConn()
+	if e != nil {
+		return PeerCredentials{}, false
+	}
+
+	var (
+		cred *unix.Ucred
+		se   error
+	)
+
+	if e := rc.Control(func(fd uintptr) {
+		cred, se = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); e != nil || se != nil {
+		return PeerCredentials{}, false
+	}
+
+	return PeerCredentials{PID: cred.Pid, UID: cred.Uid, GID: cred.Gid}, true
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package webfmwk
+
+import "net"
+
+// peerCredentials is not supported.
+func peerCredentials(net.Conn) (PeerCredentials, bool) {
+	return PeerCredentials{}, false
+}
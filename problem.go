@@ -0,0 +1,215 @@
+package webfmwk
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// HeaderRequestID hold the header name to which the request ID is attached.
+	HeaderRequestID = "X-Request-Id"
+
+	// ProblemContentType is the RFC 9457 problem details content type.
+	ProblemContentType = "application/problem+json"
+
+	_problemKey   = "webfmwk::problem"
+	_aboutBlank   = "about:blank"
+	_extErrors    = "errors"
+	_extRequestID = "request_id"
+)
+
+type (
+	// Problem implement the RFC 9457 problem details object.
+	// It may be used as an ErrorHandled content to specify a custom problem type.
+	//
+	//	return webfmwk.NewForbidden(webfmwk.Problem{
+	//		Type:   "https://example.com/probs/out-of-credit",
+	//		Title:  "You do not have enough credit.",
+	//		Detail: "Your current balance is 30, but that costs 50.",
+	//	})
+	Problem struct {
+		// Extensions hold the extension members, flattened at encoding time.
+		Extensions map[string]interface{} `json:"-"`
+
+		// Type is an URI reference identifying the problem type.
+		// Default to about:blank.
+		Type string `json:"type"`
+
+		// Title is a short human-readable summary of the problem type.
+		Title string `json:"title"`
+
+		// Detail is a human-readable explanation of this occurrence of the problem.
+		Detail string `json:"detail,omitempty"`
+
+		// Instance is an URI reference identifying this occurrence of the problem.
+		Instance string `json:"instance,omitempty"`
+
+		// Status is the http status code.
+		Status int `json:"status"`
+	}
+
+	// ProblemFieldError is a validation problem extension member item.
+	ProblemFieldError struct {
+		// Pointer is a JSON pointer to the invalid field.
+		Pointer string `json:"pointer"`
+
+		// Detail explain why the field is invalid.
+		Detail string `json:"detail"`
+	}
+)
+
+// NewProblem generate a about:blank Problem for the status code.
+func NewProblem(status int, detail string) Problem {
+	return Problem{
+		Type:   _aboutBlank,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Error implement the error interface.
+func (p Problem) Error() string {
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+
+	return p.Title
+}
+
+// With return a copy of the problem holding the k extension member.
+func (p Problem) With(k string, v interface{}) Problem {
+	ext := make(map[string]interface{}, len(p.Extensions)+1)
+	for key, val := range p.Extensions {
+		ext[key] = val
+	}
+
+	ext[k] = v
+	p.Extensions = ext
+
+	return p
+}
+
+// MarshalJSON implement the json.Marshaler interface, flattening the extension members.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+
+	b, e := json.Marshal(problem(p))
+	if e != nil || len(p.Extensions) == 0 {
+		return b, e
+	}
+
+	keys := make([]string, 0, len(p.Extensions))
+	for k := range p.Extensions {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	buf := bytes.NewBuffer(b[:len(b)-1])
+
+	for _, k := range keys {
+		v, e := json.Marshal(p.Extensions[k])
+		if e != nil {
+			return nil, e
+		}
+
+		kb, _ := json.Marshal(k)
+
+		buf.WriteByte(',')
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// isProblemMode return true if the server answer errors as problem details.
+func isProblemMode(c Context) bool {
+	return c.GetFastContext().UserValue(_problemKey) != nil
+}
+
+// toProblem convert the content of an ErrorHandled to a Problem.
+func toProblem(c Context, status int, content interface{}) Problem {
+	var p Problem
+
+	switch v := content.(type) {
+	case Problem:
+		p = v
+	case *Problem:
+		p = *v
+	case Error:
+		p = NewProblem(status, v.Message)
+	case ValidationError:
+		p = NewProblem(status, "the request is invalid").
+			With(_extErrors, validationToFieldErrors(v.Error))
+	case string:
+		p = NewProblem(status, v)
+	case error:
+		p = NewProblem(status, v.Error())
+	default:
+		p = NewProblem(status, "")
+	}
+
+	if p.Type == "" {
+		p.Type = _aboutBlank
+	}
+
+	if p.Title == "" {
+		p.Title = http.StatusText(status)
+	}
+
+	p.Status = status
+
+	fc := c.GetFastContext()
+
+	if p.Instance == "" {
+		p.Instance = string(fc.Path())
+	}
+
+	if rid := fc.Response.Header.Peek(HeaderRequestID); len(rid) > 0 {
+		p = p.With(_extRequestID, string(rid))
+	} else if rid = fc.Request.Header.Peek(HeaderRequestID); len(rid) > 0 {
+		p = p.With(_extRequestID, string(rid))
+	}
+
+	return p
+}
+
+// validationToFieldErrors convert the validation errors to problem field errors.
+func validationToFieldErrors(ev ErrorValidation) []ProblemFieldError {
+	keys := make([]string, 0, len(ev))
+	for k := range ev {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	ret := make([]ProblemFieldError, len(keys))
+	for i, k := range keys {
+		ret[i] = ProblemFieldError{Pointer: "#/" + k, Detail: ev[k]}
+	}
+
+	return ret
+}
+
+// Problem implement Context.
+func (c *icontext) Problem(p Problem) error {
+	data, e := json.Marshal(p)
+	if e != nil {
+		return fmt.Errorf("cannot encode problem details : %w", e)
+	}
+
+	_ = c.JSONBlob(p.Status, data)
+	c.SetContentType(ProblemContentType)
+	c.SetHeader("Produce", ProblemContentType)
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package webfmwk
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestProblemMarshal(t *testing.T) {
+	b, e := NewProblem(http.StatusForbidden, "no credit").
+		With("balance", 30).
+		With("accounts", []string{"/account/1"}).
+		MarshalJSON()
+
+	require.Nil(t, e)
+	assert.Equal(t, `{"type":"about:blank","title":"Forbidden","detail":"no credit","status":403,`+
+		`"accounts":["/account/1"],"balance":30}`, string(b))
+}
+
+func TestProblemDetails(t *testing.T) {
+	s, e := InitServer(WithProblemDetails())
+	require.Nil(t, e)
+
+	s.GET("/handled", func(c Context) error {
+		return NewConflict(NewError("already exist"))
+	})
+	s.GET("/raw", func(c Context) error {
+		return errors.New("boom")
+	})
+	s.GET("/custom", func(c Context) error {
+		return NewForbidden(Problem{Type: "https://example.com/probs/out-of-credit", Title: "out of credit"})
+	})
+	s.POST("/validate", func(c Context) error {
+		var form struct {
+			Firstname string `json:"first_name" validate:"required,alpha"`
+			Lastname  string `json:"last_name"  validate:"required"`
+		}
+
+		return c.FetchAndValidateContent(&form)
+	})
+
+	do := func(method, uri string) *fasthttp.RequestCtx {
+		fc := &fasthttp.RequestCtx{}
+		fc.Request.Header.SetMethod(method)
+		fc.Request.SetRequestURI(uri)
+		fc.Request.Header.Set(HeaderRequestID, "42")
+
+		if method == POST {
+			fc.Request.Header.SetContentType(MIMEJSON)
+			fc.Request.SetBodyString(`{"first_name":"jean1"}`)
+		}
+
+		s.GetRouter().Handler(fc)
+
+		return fc
+	}
+
+	tests := map[string]struct {
+		method, uri string
+		code        int
+		body        string
+	}{
+		"error handled": {
+			method: GET, uri: "/handled", code: http.StatusConflict,
+			body: `{"type":"about:blank","title":"Conflict","detail":"already exist","instance":"/handled",` +
+				`"status":409,"request_id":"42"}`,
+		},
+		"raw error": {
+			method: GET, uri: "/raw", code: http.StatusInternalServerError,
+			body: `{"type":"about:blank","title":"Internal Server Error","detail":"boom","instance":"/raw",` +
+				`"status":500,"request_id":"42"}`,
+		},
+		"custom problem": {
+			method: GET, uri: "/custom", code: http.StatusForbidden,
+			body: `{"type":"https://example.com/probs/out-of-credit","title":"out of credit","instance":"/custom",` +
+				`"status":403,"request_id":"42"}`,
+		},
+		"validation": {
+			method: POST, uri: "/validate", code: http.StatusUnprocessableEntity,
+			body: `{"type":"about:blank","title":"Unprocessable Entity","detail":"the request is invalid",` +
+				`"instance":"/validate","status":422,"errors":[` +
+				`{"pointer":"#/first_name","detail":"first_name can only contain alphabetic characters"},` +
+				`{"pointer":"#/last_name","detail":"last_name is a required field"}],"request_id":"42"}`,
+		},
+		"not found": {
+			method: GET, uri: "/undef", code: http.StatusNotFound,
+			body: `{"type":"about:blank","title":"Not Found","detail":"no such resource","instance":"/undef",` +
+				`"status":404,"request_id":"42"}`,
+		},
+		"not allowed": {
+			method: DELETE, uri: "/handled", code: http.StatusMethodNotAllowed,
+			body: `{"type":"about:blank","title":"Method Not Allowed","detail":"DELETE is not allowed on this resource",` +
+				`"instance":"/handled","status":405,"request_id":"42"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fc := do(test.method, test.uri)
+			assert.Equal(t, test.code, fc.Response.StatusCode())
+			assert.Equal(t, ProblemContentType, string(fc.Response.Header.ContentType()))
+			assert.Equal(t, test.body, string(fc.Response.Body()))
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+)
+
+const (
+	_envNotifySocket = "NOTIFY_SOCKET"
+
+	_sdReady    = "READY=1"
+	_sdStopping = "STOPPING=1"
+)
+
+// ErrServerStopped is returned by WaitReady if the server stopped before
+// being ready.
+var ErrServerStopped = errors.New("server stopped")
+
+// readiness track the listeners being served.
+type readiness struct {
+	chans map[string]chan struct{}
+	all   chan struct{}
+	once  sync.Once
+	mu    sync.Mutex
+	// batch is true while Serve start its addresses
+	batch bool
+}
+
+func newReadiness() *readiness {
+	return &readiness{chans: make(map[string]chan struct{}), all: make(chan struct{})}
+}
+
+// get return the channel of the key listener, created if needed.
+// The lock must be held.
+func (r *readiness) get(key string) chan struct{} {
+	c, ok := r.chans[key]
+	if !ok {
+		c = make(chan struct{})
+		r.chans[key] = c
+	}
+
+	return c
+}
+
+// keys return the keys identifying the l listener.
+func (l Listener) keys() []string {
+	if l.Name == "" || l.Name == l.Addr {
+		return []string{l.Addr}
+	}
+
+	return []string{l.Name, l.Addr}
+}
+
+// Ready return a channel closed once the name Address is bound and served.
+// The name is the Address Name or Addr. The channel of a listener stopped
+// via Reconfigure is replaced, so a new call wait for its restart.
+func (s *Server) Ready(name string) <-chan struct{} {
+	s.ready.mu.Lock()
+	defer s.ready.mu.Unlock()
+
+	return s.ready.get(name)
+}
+
+// WaitReady block until the server is ready, which is once all the Address
+// passed to Serve or Run are served, or once a listener started via the
+// Start methods or ServeListener is served.
+// The ctx context error is returned if it expire first, the server failure
+// or ErrServerStopped if the server stop first.
+func (s *Server) WaitReady(ctx context.Context) error {
+	select {
+	case <-s.ready.all:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.ctx.Done():
+		// the server may be ready and already stopped
+		select {
+		case <-s.ready.all:
+			return nil
+		default:
+		}
+
+		if e := s.getFailure(); e != nil {
+			return e
+		}
+
+		return ErrServerStopped
+	}
+}
+
+// WithSystemdNotify notify systemd (see sd_notify(3)) once the server is
+// ready and when it start its shutdown sequence. It's a no-op if the
+// NOTIFY_SOCKET environment variable isn't set.
+func WithSystemdNotify() Option {
+	return func(s *Server) {
+		s.meta.sdNotify = true
+		s.slog.Debug("\t-- systemd notify enabled")
+	}
+}
+
+// markReady close the l listener ready channels. Outside of Serve, the server
+// is then ready.
+func (s *Server) markReady(l Listener) {
+	s.ready.mu.Lock()
+
+	for _, key := range l.keys() {
+		c := s.ready.get(key)
+
+		select {
+		case <-c:
+		default:
+			close(c)
+		}
+	}
+
+	batch := s.ready.batch
+	s.ready.mu.Unlock()
+
+	s.slog.Debug("listener ready", slog.String("address", l.Addr))
+
+	if !batch {
+		s.serverReady()
+	}
+}
+
+// markStopped reset the l listener ready channels.
+func (s *Server) markStopped(l Listener) {
+	s.ready.mu.Lock()
+	defer s.ready.mu.Unlock()
+
+	for _, key := range l.keys() {
+		delete(s.ready.chans, key)
+	}
+}
+
+// startBatch delay the server readiness until endBatch is called.
+func (s *Server) startBatch() {
+	s.ready.mu.Lock()
+	s.ready.batch = true
+	s.ready.mu.Unlock()
+}
+
+// endBatch end the batch started by startBatch, the server being ready
+// if ok is true.
+func (s *Server) endBatch(ok bool) {
+	s.ready.mu.Lock()
+	s.ready.batch = false
+	s.ready.mu.Unlock()
+
+	if ok {
+		s.serverReady()
+	}
+}
+
+// serverReady close the WaitReady channel, send on the IsReady one and
+// notify systemd, once.
+func (s *Server) serverReady() {
+	s.ready.once.Do(func() {
+		s.slog.Info("server is up")
+		close(s.ready.all)
+
+		go func() {
+			select {
+			case s.isReady <- true:
+			case <-s.ctx.Done():
+			}
+		}()
+
+		s.notify(_sdReady)
+	})
+}
+
+// notify send the state to systemd if enabled.
+func (s *Server) notify(state string) {
+	if !s.meta.sdNotify {
+		return
+	}
+
+	if e := sdNotify(os.Getenv(_envNotifySocket), state); e != nil {
+		s.slog.Error("notifying systemd", slog.String("state", state), slog.Any("error", e))
+	}
+}
+
+// sdNotify send the state datagram to the socket unix socket, an empty
+// socket being a no-op. The '@' prefix denote an abstract socket.
+func sdNotify(socket, state string) error {
+	if socket == "" {
+		return nil
+	}
+
+	c, e := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if e != nil {
+		return fmt.Errorf("dialing %q: %w", socket, e)
+	}
+
+	defer c.Close()
+
+	_, e = c.Write([]byte(state))
+
+	return e
+}
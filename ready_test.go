@@ -0,0 +1,128 @@
+package webfmwk
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReady(t *testing.T) {
+	notify, e := net.ListenUnixgram("unixgram", &net.UnixAddr{
+		Name: filepath.Join(t.TempDir(), "notify.sock"), Net: "unixgram",
+	})
+	require.Nil(t, e)
+
+	defer notify.Close()
+
+	t.Setenv(_envNotifySocket, notify.LocalAddr().String())
+
+	s, e := InitServer(WithSystemdNotify())
+	require.Nil(t, e)
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		sock        = "unix://" + filepath.Join(t.TempDir(), "ready.sock")
+		tlsAddr     = freeAddr(t)
+		done        = make(chan error, 1)
+	)
+
+	defer cancel()
+
+	// not ready until Serve bound all the addresses
+	early := s.Ready("api")
+
+	go func() {
+		done <- s.Serve(ctx,
+			Address{Name: "api", Addr: freeAddr(t)},
+			Address{Addr: sock},
+			Address{Name: "secure", Addr: tlsAddr, TLS: &tls.Config{
+				Cert: "./example/ssl.crt", Key: "./example/ssl.key", Insecure: true,
+			}})
+	}()
+
+	wctx, wcancel := context.WithTimeout(ctx, 5*time.Second)
+	defer wcancel()
+
+	require.Nil(t, s.WaitReady(wctx))
+
+	for _, c := range []<-chan struct{}{early, s.Ready("api"), s.Ready(sock), s.Ready("secure"), s.Ready(tlsAddr)} {
+		select {
+		case <-c:
+		default:
+			t.Fatal("listener not ready")
+		}
+	}
+
+	select {
+	case <-s.Ready("unknown"):
+		t.Fatal("unknown listener ready")
+	default:
+	}
+
+	// legacy channel
+	assert.True(t, <-s.IsReady())
+
+	buf := make([]byte, 64)
+
+	require.Nil(t, notify.SetReadDeadline(time.Now().Add(time.Second)))
+	n, e := notify.Read(buf)
+	require.Nil(t, e)
+	assert.Equal(t, _sdReady, string(buf[:n]))
+
+	cancel()
+	require.Nil(t, <-done)
+
+	n, e = notify.Read(buf)
+	require.Nil(t, e)
+	assert.Equal(t, _sdStopping, string(buf[:n]))
+}
+
+func TestWaitReady(t *testing.T) {
+	t.Run("start", func(t *testing.T) {
+		s, e := InitServer()
+		require.Nil(t, e)
+
+		defer func() { require.Nil(t, s.ShutdownAndWait()) }()
+
+		addr := freeAddr(t)
+		go s.Start(addr)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		require.Nil(t, s.WaitReady(ctx))
+		<-s.Ready(addr)
+	})
+
+	t.Run("bind failure", func(t *testing.T) {
+		busy, e := net.Listen("tcp4", "127.0.0.1:0")
+		require.Nil(t, e)
+
+		defer busy.Close()
+
+		s, e := InitServer()
+		require.Nil(t, e)
+
+		go func() {
+			_ = s.Serve(context.Background(), Address{Addr: freeAddr(t)}, Address{Addr: busy.Addr().String()})
+		}()
+
+		assert.ErrorIs(t, s.WaitReady(context.Background()), ErrAddressInUse)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		s, e := InitServer()
+		require.Nil(t, e)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, s.WaitReady(ctx), context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,222 @@
+package webfmwk
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+)
+
+// ReconfigureReport hold the outcome of a Reconfigure call.
+type ReconfigureReport struct {
+	// Failed hold the errors per address. The invalid addresses are keyed by
+	// their index in the Reconfigure set, ex: addrs[2].
+	Failed map[string]error `json:"-"`
+
+	// Started hold the newly started listeners.
+	Started []Listener `json:"started"`
+
+	// Stopped hold the gracefully stopped listeners.
+	Stopped []Listener `json:"stopped"`
+
+	// Restarted hold the listeners restarted because they switched from
+	// plain http to TLS, or back.
+	Restarted []Listener `json:"restarted"`
+
+	// Reloaded hold the TLS listeners whose TLS config changed, the new
+	// material being served without restarting them.
+	Reloaded []Listener `json:"reloaded"`
+
+	// Unchanged hold the listeners left untouched.
+	Unchanged []Listener `json:"unchanged"`
+}
+
+// Err return the report failures joined in a single error, nil if none.
+func (r ReconfigureReport) Err() error {
+	errs := make([]error, 0, len(r.Failed))
+	for _, e := range r.Failed {
+		errs = append(errs, e)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Reconfigure apply the addrs set of Address to the running server:
+//   - the addresses not yet listened on are started
+//   - the listeners whose address isn't part of addrs anymore are gracefully
+//     stopped, bounded by the SetShutdownTimeout value
+//   - the TLS listeners whose TLS config changed serve the new material,
+//     their connections kept alive
+//   - the listeners switching from plain http to TLS, or back, are restarted
+//   - the other listeners are left untouched, their connections kept alive
+//
+// The listeners are identified by their address. A failure on one address
+// doesn't prevent the others to be applied. The new TLS material is loaded
+// before touching a listener, which keep serving the previous one if it's
+// invalid. A listener whose drain deadline is reached is still restarted,
+// the stop error being reported. An invalid address is reported and the
+// listener bound to it, if any, left untouched. The admin listener (see
+// WithAdminAddress) is left untouched.
+func (s *Server) Reconfigure(addrs Addresses) ReconfigureReport {
+	s.reconfMu.Lock()
+	defer s.reconfMu.Unlock()
+
+	var (
+		report  = ReconfigureReport{Failed: make(map[string]error)}
+		wanted  = make(map[string]Address, len(addrs))
+		invalid = make(map[string]bool)
+	)
+
+	for i := range addrs {
+		if e := addrs[i].Validate(); e != nil {
+			report.Failed[fmt.Sprintf("addrs[%d]", i)] = fmt.Errorf("address %q: %w", addrs[i].GetName(), e)
+			invalid[addrs[i].GetAddr()] = true
+
+			continue
+		}
+
+		wanted[addrs[i].GetAddr()] = addrs[i]
+	}
+
+	for _, ln := range s.listeners.snapshot() {
+		if ln.Admin {
+			continue
+		}
+
+		addr, ok := wanted[ln.Addr]
+
+		switch {
+		case !ok && invalid[ln.Addr]:
+			report.Unchanged = append(report.Unchanged, ln.Listener)
+
+		case !ok:
+			if e := s.stopListener(ln); e != nil {
+				report.Failed[ln.Addr] = e
+			}
+
+			report.Stopped = append(report.Stopped, ln.Listener)
+
+		case sameTLS(ln.cfg, addr.GetTLS()):
+			report.Unchanged = append(report.Unchanged, ln.Listener)
+
+		case ln.reloader != nil && hasTLS(addr.GetTLS()):
+			if e := ln.reloader.Swap(addr.GetTLS()); e != nil {
+				report.Failed[ln.Addr] = listenError(ln.Listener, e)
+				report.Unchanged = append(report.Unchanged, ln.Listener)
+			} else {
+				ln.cfg = addr.GetTLS()
+				report.Reloaded = append(report.Reloaded, ln.Listener)
+			}
+
+		default:
+			if e := s.checkTLS(addr); e != nil {
+				report.Failed[ln.Addr] = listenError(ln.Listener, e)
+				report.Unchanged = append(report.Unchanged, ln.Listener)
+
+				break
+			}
+
+			// the listener is closed even if the drain deadline is reached
+			stopErr := s.stopListener(ln)
+
+			if nln, e := s.startAddress(addr); e != nil {
+				report.Failed[ln.Addr] = errors.Join(stopErr, e)
+				report.Stopped = append(report.Stopped, ln.Listener)
+			} else {
+				if stopErr != nil {
+					report.Failed[ln.Addr] = stopErr
+				}
+
+				report.Restarted = append(report.Restarted, nln.Listener)
+			}
+		}
+
+		delete(wanted, ln.Addr)
+	}
+
+	// start the new addresses, in the addrs order
+	for i := range addrs {
+		addr, ok := wanted[addrs[i].GetAddr()]
+		if !ok {
+			continue
+		}
+
+		delete(wanted, addr.GetAddr())
+
+		if nln, e := s.startAddress(addr); e != nil {
+			report.Failed[addr.GetAddr()] = e
+		} else {
+			report.Started = append(report.Started, nln.Listener)
+		}
+	}
+
+	s.slog.Info("reconfigured",
+		slog.Int("started", len(report.Started)),
+		slog.Int("stopped", len(report.Stopped)),
+		slog.Int("restarted", len(report.Restarted)),
+		slog.Int("reloaded", len(report.Reloaded)),
+		slog.Int("unchanged", len(report.Unchanged)),
+		slog.Int("failed", len(report.Failed)))
+
+	return report
+}
+
+// stopListener gracefully stop the ln listener, force closing its
+// connections once the SetShutdownTimeout deadline is reached.
+func (s *Server) stopListener(ln *listener) error {
+	ln.detached.Store(true)
+	s.listeners.remove(ln)
+	s.markStopped(ln.Listener)
+
+	ctx, cancel := s.shutdownContext()
+	defer cancel()
+
+	s.slog.Info("stopping listener", slog.String("address", ln.Addr))
+
+	// the listener may not be served yet
+	_ = ln.nl.Close()
+
+	if e := ln.server.ShutdownWithContext(ctx); e != nil {
+		s.slog.Warn("deadline reached, force closing listener connections",
+			slog.String("address", ln.Addr), slog.Int("connections", ln.conns.closeAll()))
+
+		return fmt.Errorf("stopping listener %q: %w", ln.Addr, e)
+	}
+
+	return nil
+}
+
+// checkTLS return an error if the addr listener can't be served with its
+// TLS config, or without if it has none.
+func (s *Server) checkTLS(addr Address) error {
+	if !hasTLS(addr.GetTLS()) {
+		if ep, _ := addr.Endpoint(); s.meta.http2 && !ep.IsUnix() {
+			return fmt.Errorf("%w: plain http listener", ErrHTTP2RequireTLS)
+		}
+
+		return nil
+	}
+
+	_, e := tls.NewReloader(addr.GetTLS(), s.Logger(LogTLS))
+
+	return e
+}
+
+// hasTLS return true if the cfg tls config is set.
+func hasTLS(cfg tls.IConfig) bool { return cfg != nil && !cfg.Empty() }
+
+// sameTLS return true if both tls config are equivalent, nil and empty
+// config being the same.
+func sameTLS(a, b tls.IConfig) bool {
+	var (
+		aok = hasTLS(a)
+		bok = hasTLS(b)
+	)
+
+	if !aok || !bok {
+		return aok == bok
+	}
+
+	return a.SameAs(b)
+}
@@ -0,0 +1,211 @@
+package webfmwk
+
+import (
+	"context"
+	fmtls "crypto/tls"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/gommon/port"
+	"github.com/burgesQ/webfmwk/v6/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	p, e := port.GetFree()
+	require.Nil(t, e)
+
+	return fmt.Sprintf("127.0.0.1:%d", p)
+}
+
+func TestReconfigure(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	s.GET("/ping", func(c Context) error { return c.JSONOk(_pong) })
+
+	defer func() {
+		require.Nil(t, s.Shutdown())
+		s.WaitForStop()
+	}()
+
+	var (
+		kept, removed, added = freeAddr(t), freeAddr(t), freeAddr(t)
+		ping                 = func(addr string) error {
+			resp, e := http.Get("http://" + addr + "/ping") //nolint:noctx
+			if e == nil {
+				resp.Body.Close()
+			}
+
+			return e
+		}
+	)
+
+	report := s.Reconfigure(Addresses{{Addr: kept, Name: "kept"}, {Addr: removed, Name: "removed"}})
+	require.Nil(t, report.Err())
+	assert.Len(t, report.Started, 2)
+	require.Nil(t, ping(kept))
+	require.Nil(t, ping(removed))
+
+	report = s.Reconfigure(Addresses{
+		{Addr: kept, Name: "kept"},
+		{Addr: added, Name: "added"},
+		{Name: "invalid"},
+		{Name: "other invalid"},
+	})
+
+	assert.NotNil(t, report.Err())
+	assert.Len(t, report.Failed, 2)
+	assert.Contains(t, report.Failed, "addrs[2]")
+	assert.Contains(t, report.Failed, "addrs[3]")
+	assert.Equal(t, []Listener{{Name: "kept", Addr: kept}}, report.Unchanged)
+	assert.Equal(t, []Listener{{Name: "removed", Addr: removed}}, report.Stopped)
+	assert.Equal(t, []Listener{{Name: "added", Addr: added}}, report.Started)
+	assert.Empty(t, report.Restarted)
+
+	assert.Nil(t, s.GetContext().Err(), "the server must keep running")
+	assert.Nil(t, ping(kept))
+	assert.Nil(t, ping(added))
+	assert.NotNil(t, ping(removed))
+	assert.Len(t, s.listeners.snapshot(), 2)
+}
+
+func TestReconfigureForcedRestart(t *testing.T) {
+	var (
+		addr     = freeAddr(t)
+		release  = make(chan struct{})
+		started  = make(chan struct{})
+		inflight = make(chan struct{})
+	)
+
+	s, e := InitServer(SetShutdownTimeout(50 * time.Millisecond))
+	require.Nil(t, e)
+
+	s.GET("/ping", func(c Context) error { return c.JSONOk(_pong) })
+	s.GET("/block", func(c Context) error {
+		close(started)
+		<-release
+
+		return c.JSONOk(_pong)
+	})
+
+	defer func() {
+		close(release)
+		require.Nil(t, s.Shutdown())
+		s.WaitForStop()
+	}()
+
+	report := s.Reconfigure(Addresses{{Addr: addr, Name: "web"}})
+	require.Nil(t, report.Err())
+
+	go func() {
+		defer close(inflight)
+
+		if resp, e := http.Get("http://" + addr + "/block"); e == nil { //nolint:noctx
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+
+	// the in-flight request exceed the drain deadline
+	report = s.Reconfigure(Addresses{{Addr: addr, Name: "web", TLS: &tls.Config{
+		Cert: "./example/ssl.crt", Key: "./example/ssl.key", Insecure: true,
+	}}})
+
+	require.Contains(t, report.Failed, addr)
+	assert.ErrorIs(t, report.Failed[addr], context.DeadlineExceeded)
+	assert.Equal(t, []Listener{{Name: "web", Addr: addr, TLS: true}}, report.Restarted)
+	assert.Empty(t, report.Stopped)
+	<-inflight
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &fmtls.Config{InsecureSkipVerify: true}, //nolint:gosec
+	}}
+
+	resp, e := client.Get("https://" + addr + "/ping") //nolint:noctx
+	require.Nil(t, e)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestReconfigureTLS(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	s.GET("/ping", func(c Context) error { return c.JSONOk(_pong) })
+
+	defer func() {
+		require.Nil(t, s.Shutdown())
+		s.WaitForStop()
+	}()
+
+	var (
+		secure, plain = freeAddr(t), "http://" + freeAddr(t)
+		valid         = &tls.Config{Cert: "./example/ssl.crt", Key: "./example/ssl.key", Insecure: true}
+		other         = &tls.Config{Cert: "./example/server.cert", Key: "./example/server.key", Insecure: true}
+		missing       = &tls.Config{Cert: "missing", Key: "missing", Insecure: true}
+		client        = &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &fmtls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		}}
+		ping = func(uri string) error {
+			resp, e := client.Get(uri) //nolint:noctx
+			if e == nil {
+				resp.Body.Close()
+			}
+
+			return e
+		}
+	)
+
+	report := s.Reconfigure(Addresses{{Addr: secure, TLS: valid}, {Addr: plain}})
+	require.Nil(t, report.Err())
+
+	ln := s.listeners.snapshot()[0]
+
+	t.Log("a changed TLS config is served without restarting the listener")
+	{
+		report = s.Reconfigure(Addresses{{Addr: secure, TLS: other}, {Addr: plain}})
+		require.Nil(t, report.Err())
+		assert.Equal(t, []Listener{{Addr: secure, TLS: true}}, report.Reloaded)
+		assert.Empty(t, report.Restarted)
+		assert.Same(t, ln, s.listeners.snapshot()[0])
+
+		want, e := fmtls.LoadX509KeyPair(other.Cert, other.Key)
+		require.Nil(t, e)
+
+		resp, e := client.Get("https://" + secure + "/ping") //nolint:noctx
+		require.Nil(t, e)
+		resp.Body.Close()
+		assert.Equal(t, want.Certificate[0], resp.TLS.PeerCertificates[0].Raw)
+	}
+
+	t.Log("an invalid TLS config keep the listeners serving")
+	{
+		report = s.Reconfigure(Addresses{{Addr: secure, TLS: missing}, {Addr: plain, TLS: missing}})
+		assert.ErrorIs(t, report.Failed[secure], ErrTLSCertificate)
+		assert.Contains(t, report.Failed, "addrs[1]")
+		assert.Len(t, report.Unchanged, 2)
+		assert.Empty(t, report.Stopped)
+		require.Nil(t, ping("https://"+secure+"/ping"))
+		require.Nil(t, ping(plain+"/ping"))
+	}
+
+	t.Log("an invalid plain to TLS switch keep the listener serving")
+	{
+		bare := freeAddr(t)
+
+		require.Nil(t, s.Reconfigure(Addresses{{Addr: secure, TLS: other}, {Addr: plain}, {Addr: bare}}).Err())
+
+		report = s.Reconfigure(Addresses{{Addr: secure, TLS: other}, {Addr: plain}, {Addr: bare, TLS: missing}})
+		assert.ErrorIs(t, report.Failed[bare], ErrTLSCertificate)
+		assert.Empty(t, report.Restarted)
+		assert.Empty(t, report.Stopped)
+		require.Nil(t, ping("http://"+bare+"/ping"))
+	}
+}
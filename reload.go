@@ -0,0 +1,66 @@
+package webfmwk
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReloadTLS reload the TLS material of all the https listeners.
+// On failure the listener keep serving its previous material, and
+// the errors are returned.
+func (s *Server) ReloadTLS() error {
+	var errs []error
+
+	for _, ln := range s.listeners.snapshot() {
+		if ln.reloader == nil {
+			continue
+		}
+
+		if e := ln.reloader.Reload(); e != nil {
+			errs = append(errs, e)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// watchTLS start polling the ln listener tls files, if the tls reload is enabled.
+// The watcher stop with the listener.
+func (s *Server) watchTLS(ln *listener) {
+	if s.meta.tlsReload <= 0 {
+		return
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		lg := s.Logger(LogTLS)
+
+		lg.Debug("tls watcher: starting", slog.String("address", ln.Addr))
+		ln.reloader.Watch(ln.ctx, s.meta.tlsReload)
+		lg.Info("tls watcher: done", slog.String("address", ln.Addr))
+	}()
+}
+
+// reloadHandler reload the tls material on SIGHUP, until the server stop.
+func (s *Server) reloadHandler() {
+	c := make(chan os.Signal, 1)
+
+	signal.Notify(c, syscall.SIGHUP)
+	defer signal.Stop(c)
+
+	for {
+		select {
+		case <-c:
+			s.Logger(LogTLS).Info("captured SIGHUP, reloading the tls material")
+			_ = s.ReloadTLS()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package webfmwk
+
+import (
+	fmtls "crypto/tls"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadTLSSignal(t *testing.T) {
+	var (
+		dir       = t.TempDir()
+		cert, key = filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+		addr      = freeAddr(t)
+		copyPair  = func(crt, k string) {
+			for src, dst := range map[string]string{crt: cert, k: key} {
+				raw, e := os.ReadFile(src)
+				require.Nil(t, e)
+				require.Nil(t, os.WriteFile(dst, raw, 0o600))
+			}
+		}
+	)
+
+	// keep the test process alive whatever the handlers state
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+
+	defer signal.Stop(sig)
+
+	copyPair("./example/ssl.crt", "./example/ssl.key")
+
+	s, e := InitServer(WithCtrlC(), WithTLSReloadSignal())
+	require.Nil(t, e)
+
+	s.GET("/ping", func(c Context) error { return c.JSONOk(_pong) })
+
+	require.Nil(t, s.Reconfigure(Addresses{{Addr: addr, TLS: &tls.Config{Cert: cert, Key: key, Insecure: true}}}).Err())
+
+	defer func() { require.Nil(t, s.ShutdownAndWait()) }()
+
+	want, e := fmtls.LoadX509KeyPair("./example/server.cert", "./example/server.key")
+	require.Nil(t, e)
+
+	copyPair("./example/server.cert", "./example/server.key")
+
+	served := func() []byte {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &fmtls.Config{InsecureSkipVerify: true}, //nolint:gosec
+			DisableKeepAlives: true,
+		}}
+
+		resp, e := client.Get("https://" + addr + "/ping") //nolint:noctx
+		require.Nil(t, e)
+		resp.Body.Close()
+
+		return resp.TLS.PeerCertificates[0].Raw
+	}
+
+	require.Eventually(t, func() bool {
+		require.Nil(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+		return string(served()) == string(want.Certificate[0])
+	}, 5*time.Second, 50*time.Millisecond)
+
+	assert.Nil(t, s.GetContext().Err(), "the SIGHUP must not stop the server")
+}
@@ -1,6 +1,10 @@
 package webfmwk
 
-import "log/slog"
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+)
 
 type (
 	// Header represent a header in a string key:value form.
@@ -10,6 +14,15 @@ type (
 	SendResponse interface {
 		JSONResponse
 
+		// Render encode the content with the codec matching the request Accept
+		// header and send it with the op status code. The json codec is used
+		// if the header is missing, ErrNotAcceptable is returned if no codec match.
+		Render(op int, content interface{}) error
+
+		// Problem answer the p RFC 9457 problem details, using the
+		// application/problem+json content type.
+		Problem(p Problem) error
+
 		// SendResponse create & send a response according to the parameters.
 		SendResponse(op int, content []byte, headers ...Header) error
 
@@ -45,6 +58,31 @@ func (c *icontext) IsPretty() bool {
 	return c.QueryArgs().Has(_prettyTag)
 }
 
+// Render implement Context
+func (c *icontext) Render(statusCode int, content interface{}) error {
+	codec, ok := c.codecs.forAccept(string(c.Request.Header.Peek("Accept")))
+	if !ok {
+		return ErrNotAcceptable
+	}
+
+	c.SetHeader("Vary", "Accept")
+
+	if _, ok := codec.(jsonCodec); ok {
+		return c.JSON(statusCode, content)
+	}
+
+	data, e := codec.Marshal(content)
+	if e != nil {
+		return fmt.Errorf("cannot %s response : %w", codec.ContentType(), e)
+	}
+
+	if statusCode != http.StatusNoContent {
+		c.SetContentType(codec.ContentType())
+	}
+
+	return c.response(statusCode, data)
+}
+
 // SendResponse implement Context
 func (c *icontext) SendResponse(statusCode int, content []byte, headers ...Header) error {
 	c.setHeaders(headers...)
@@ -3,6 +3,7 @@ package webfmwk
 import (
 	"context"
 	"log/slog"
+	"net/http"
 
 	"github.com/fasthttp/router"
 	"github.com/segmentio/encoding/json"
@@ -26,6 +27,8 @@ const (
 	ANY = "ANY"
 
 	_pingEndpoint = "/ping"
+
+	_routeKey = "webfmwk::route"
 )
 
 type (
@@ -99,7 +102,28 @@ type (
 		// Name is used in message.
 		Name string `json:"name"`
 
-		Middlewares *[]Handler
+		// Status hold the http status code of the Typed handlers response.
+		// Default to 200.
+		Status int `json:"status,omitempty"`
+
+		Middlewares *[]Handler `json:"-"`
+
+		// Limits hold the route request limits, see RouteLimits.
+		Limits RouteLimits `json:"-"`
+
+		// Summary, Description and Tags are used in the OpenAPI document.
+		Summary     string   `json:"summary,omitempty"`
+		Description string   `json:"description,omitempty"`
+		Tags        []string `json:"tags,omitempty"`
+
+		// Request hold a value of the request payload type, used in the OpenAPI document.
+		Request interface{} `json:"-"`
+
+		// Response hold a value of the response payload type, used in the OpenAPI document.
+		Response interface{} `json:"-"`
+
+		// Query hold a value of the query params type, used in the OpenAPI document.
+		Query interface{} `json:"-"`
 	}
 
 	// Routes hold an array of route.
@@ -109,7 +133,10 @@ type (
 	RoutesPerPrefix map[string]Routes
 )
 
-var _pong = json.RawMessage(`{"ping": "pong"}`)
+var (
+	_pong     = json.RawMessage(`{"ping": "pong"}`)
+	_draining = json.RawMessage(`{"ping": "draining"}`)
+)
 
 //
 // Routes method
@@ -196,6 +223,9 @@ func (s *Server) RouteApplier(rpps ...RoutesPerPrefix) {
 // - registered handlers (webfmwk/v6/handler)
 // - doc handler is registered
 // - test handler (/ping) is registered
+// - health handlers (/livez, /readyz, /healthz) are registered
+// - jobs handler (/jobs) is registered
+// unless the operational ones are served by the admin listener (see WithAdminAddress)
 // - registered fmwk routes
 func (s *Server) GetRouter() *router.Router {
 	r := router.New()
@@ -204,21 +234,40 @@ func (s *Server) GetRouter() *router.Router {
 	r.RedirectTrailingSlash, r.RedirectFixedPath = false, false
 
 	// IDEA: router.PanicHandler
-	r.NotFound, r.MethodNotAllowed = s.CustomHandler(handleNotFound), s.CustomHandler(handleNotAllowed)
+	// the unmatched requests go through the server wide Handlers too
+	r.NotFound = s.CustomHandler(handleHandlerError(s.withHandlers(handleNotFound)))
+	r.MethodNotAllowed = s.CustomHandler(handleHandlerError(s.withHandlers(handleNotAllowed)))
 
 	// register doc handler
 	if len(s.meta.docHandlers) > 0 {
 		for i := range s.meta.docHandlers {
 			h := s.meta.docHandlers[i]
 			s.slog.Info("load doc handler", slog.String("name", h.Name))
+
+			if s.hasAdmin() {
+				s.slog.Warn("doc handler served on the public listeners, see WithAdminHandlers",
+					slog.String("name", h.Name))
+			}
 			r.ANY(s.meta.prefix+h.Path, s.CustomHandler(h.H))
 		}
 	}
 
 	// register test handler
-	if s.meta.checkIsUp {
-		r.GET(s.meta.prefix+_pingEndpoint, s.CustomHandler(func(c Context) error {
-			return c.JSONOk(_pong)
+	if s.meta.checkIsUp && !s.hasAdmin() {
+		r.GET(s.meta.prefix+_pingEndpoint, s.CustomHandler(s.pingHandler))
+	}
+
+	// register health handlers
+	if s.meta.health && !s.hasAdmin() {
+		r.GET(s.meta.prefix+_livezEndpoint, s.CustomHandler(s.healthHandler(Liveness)))
+		r.GET(s.meta.prefix+_readyzEndpoint, s.CustomHandler(s.healthHandler(Readiness|Startup)))
+		r.GET(s.meta.prefix+_healthzEndpoint, s.CustomHandler(s.healthHandler(0)))
+	}
+
+	// register jobs handler
+	if s.meta.jobs && !s.hasAdmin() {
+		r.GET(s.meta.prefix+_jobsEndpoint, s.CustomHandler(func(c Context) error {
+			return c.JSONOk(s.Jobs())
 		}))
 	}
 
@@ -234,7 +283,30 @@ func (s *Server) GetRouter() *router.Router {
 			fasthttpadaptor.NewFastHTTPHandler(s.meta.socketIOHandler))
 	}
 
-	if s.meta.pprof {
+	// register the OpenAPI document
+	if s.meta.openapi {
+		s.slog.Info("loading openapi handler", slog.String("path", s.meta.prefix+s.meta.openapiPath))
+
+		if s.hasAdmin() {
+			s.slog.Warn("openapi document served on the public listeners",
+				slog.String("path", s.meta.prefix+s.meta.openapiPath))
+		}
+
+		doc, e := json.Marshal(s.OpenAPI())
+		if e != nil {
+			s.slog.Error("generating the openapi document", slog.Any("error", e))
+		}
+
+		r.GET(s.meta.prefix+s.meta.openapiPath, s.CustomHandler(handleHandlerError(func(c Context) error {
+			if e != nil {
+				return NewInternal(NewError("openapi document unavailable"))
+			}
+
+			return c.JSONBlob(http.StatusOK, doc)
+		})))
+	}
+
+	if s.meta.pprof && !s.hasAdmin() {
 		s.slog.Info("loading pprof handler", "path", "/debug/pprof/{profile:*}'")
 		r.GET(s.meta.prefix+s.meta.pprofPath, pprofhandler.PprofHandler)
 	}
@@ -252,11 +324,15 @@ func (s *Server) GetRouter() *router.Router {
 			route := routes[i]
 			handler := route.Handler
 
+			// answer a 503 to the route handler giving up on its deadline
+			if route.Limits.Timeout > 0 {
+				handler = deadlineError(handler)
+			}
+
 			// register internal Handlers
-			handler = contentIsJSON(handleHandlerError(handler))
+			handler = s.contentIsSupported(handleHandlerError(handler))
 
-			// TODO: register group wise / route wise custom Handlers
-			// if route. != nil {
+			// register route wise / group wise custom Handlers
 			if route.Middlewares != nil {
 				for _, mdlw := range *route.Middlewares {
 					handler = mdlw(handler)
@@ -264,12 +340,16 @@ func (s *Server) GetRouter() *router.Router {
 			}
 
 			// register user server wise custom Handlers
-			if s.meta.handlers != nil {
-				for _, h := range s.meta.handlers {
-					handler = h(handleHandlerError(handler))
-				}
+			handler = s.withHandlers(handler)
+
+			// apply the route limits to the whole handlers chain
+			if !route.Limits.isZero() {
+				handler = withLimits(route.Limits, handler)
 			}
 
+			// errors returned by the outer most Handler
+			handler = withRoute(route, prefix, handleHandlerError(handler))
+
 			if len(prefix) == 0 {
 				r.Handle(route.Verbe, route.Path, s.CustomHandler(handler))
 			} else {
@@ -281,10 +361,42 @@ func (s *Server) GetRouter() *router.Router {
 	return r
 }
 
+// withHandlers wrap next with the server wide Handlers, see WithHandlers.
+func (s *Server) withHandlers(next HandlerFunc) HandlerFunc {
+	for _, h := range s.meta.handlers {
+		next = h(handleHandlerError(next))
+	}
+
+	return next
+}
+
+// withRoute save the matched route in the request context.
+func withRoute(route Route, prefix string, next HandlerFunc) HandlerFunc {
+	route.Path = prefix + route.Path
+
+	return HandlerFunc(func(c Context) error {
+		c.GetFastContext().SetUserValue(_routeKey, &route)
+
+		return next(c)
+	})
+}
+
+// pingHandler reply to the /ping endpoint.
+func (s *Server) pingHandler(c Context) error {
+	if s.IsDraining() {
+		return c.JSON(http.StatusServiceUnavailable, _draining)
+	}
+
+	return c.JSONOk(_pong)
+}
+
 // CustomHandler return the webfmwk Handler main logic,
 // which return a HandlerFunc wrapper in an fasthttp.Handler.
 func (s *Server) CustomHandler(handler HandlerFunc) fasthttp.RequestHandler {
 	return func(c *fasthttp.RequestCtx) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+
 		ctx, cancel := s.genContext(c)
 		defer cancel()
 
@@ -296,5 +408,15 @@ func (s *Server) CustomHandler(handler HandlerFunc) fasthttp.RequestHandler {
 func (s *Server) genContext(c *fasthttp.RequestCtx) (Context, context.CancelFunc) {
 	ctx, fn := context.WithCancel(s.ctx)
 
-	return &icontext{c, s.slog, ctx}, fn
+	if s.meta.problem {
+		c.SetUserValue(_problemKey, true)
+	}
+
+	ic := &icontext{RequestCtx: c, slog: s.slog, loggers: s.loggers, ctx: ctx, codecs: s.meta.codecs}
+
+	if s.meta.debugHeader != "" && s.debugRequested(c.Request.Header.Peek(s.meta.debugHeader)) {
+		ic.debug, ic.slog = true, s.loggers.logger(LogServer, true)
+	}
+
+	return ic, fn
 }
@@ -0,0 +1,280 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// OverlapSkip skip a run if the previous one is still running.
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue delay a run until the previous one end. At most one run
+	// is queued, the other being skipped.
+	OverlapQueue OverlapPolicy = "queue"
+
+	_jobsEndpoint = "/jobs"
+)
+
+// ErrJobExists is returned by ScheduleJob if a job of the same name is
+// already scheduled.
+var ErrJobExists = errors.New("job already scheduled")
+
+type (
+	// OverlapPolicy define what's done when a job run is due while the
+	// previous one is still running.
+	OverlapPolicy string
+
+	// Job hold a scheduled job, see ScheduleJob.
+	Job struct {
+		// Schedule compute the job run times, see ParseSchedule and Every.
+		Schedule Schedule
+
+		// Run is the job function. The ctx context is canceled on shutdown
+		// or once the Timeout is reached.
+		Run func(ctx context.Context) error
+
+		// Name identify the job.
+		Name string
+
+		// Overlap hold the overlap policy, default to OverlapSkip.
+		Overlap OverlapPolicy
+
+		// Timeout bound the run duration, if set.
+		Timeout time.Duration
+	}
+
+	// JobStatus hold the status of a scheduled job.
+	JobStatus struct {
+		// LastRun hold the start time of the last run, zero if never run.
+		LastRun time.Time `json:"last_run,omitempty"`
+
+		// NextRun hold the next scheduled run time.
+		NextRun time.Time `json:"next_run,omitempty"`
+
+		// Name identify the job.
+		Name string `json:"name"`
+
+		// Overlap hold the job overlap policy.
+		Overlap OverlapPolicy `json:"overlap"`
+
+		// LastDuration hold the duration of the last run.
+		LastDuration string `json:"last_duration,omitempty"`
+
+		// LastError hold the error of the last run, if any.
+		LastError string `json:"last_error,omitempty"`
+
+		// Runs hold the number of ended runs.
+		Runs int `json:"runs"`
+
+		// Skipped hold the number of skipped runs.
+		Skipped int `json:"skipped"`
+
+		// Running is true while the job run.
+		Running bool `json:"running"`
+	}
+
+	// job hold a scheduled Job and its status.
+	job struct {
+		Job
+		status  JobStatus
+		pending bool
+	}
+
+	// scheduler hold the scheduled jobs of a Server, and the ones waiting
+	// for the server to be served.
+	scheduler struct {
+		jobs   map[string]*job
+		queued []Job
+		mu     sync.RWMutex
+	}
+)
+
+// WithJobs schedule the jobs once the server is served (see Serve and
+// Start), and expose their status on the `/jobs` endpoint, under the server
+// prefix. A server never served doesn't run them. See ScheduleJob.
+func WithJobs(jobs ...Job) Option {
+	return func(s *Server) {
+		s.meta.jobs = true
+
+		s.jobs.mu.Lock()
+		s.jobs.queued = append(s.jobs.queued, jobs...)
+		s.jobs.mu.Unlock()
+
+		s.slog.Debug("\t-- jobs loaded", "jobs", len(jobs))
+	}
+}
+
+// startJobs schedule the jobs queued by WithJobs.
+func (s *Server) startJobs() {
+	s.jobs.mu.Lock()
+	queued := s.jobs.queued
+	s.jobs.queued = nil
+	s.jobs.mu.Unlock()
+
+	for i := range queued {
+		if e := s.ScheduleJob(queued[i]); e != nil {
+			s.slog.Error("scheduling job", "error", e)
+		}
+	}
+}
+
+// ScheduleJob schedule the j job on the server context: the job is run
+// until the server stop, the running job being canceled on Shutdown and
+// waited by WaitForStop. A panic in the job is recovered and reported as
+// its error. See Jobs for the jobs status.
+//
+// ErrJobExists is returned if the job name is already used, and
+// ErrServerStopped if the server is stopped.
+func (s *Server) ScheduleJob(j Job) error {
+	switch {
+	case j.Name == "", j.Run == nil, j.Schedule == nil:
+		return errors.New("job: missing name, run function or schedule")
+	case j.Overlap == "":
+		j.Overlap = OverlapSkip
+	case j.Overlap != OverlapSkip && j.Overlap != OverlapQueue:
+		return fmt.Errorf("job %q: unknown overlap policy %q", j.Name, j.Overlap)
+	}
+
+	if s.ctx.Err() != nil {
+		return ErrServerStopped
+	}
+
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+
+	if _, ok := s.jobs.jobs[j.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrJobExists, j.Name)
+	}
+
+	jb := &job{Job: j, status: JobStatus{Name: j.Name, Overlap: j.Overlap}}
+	s.jobs.jobs[j.Name] = jb
+
+	s.wg.Add(1)
+
+	go s.schedule(jb)
+
+	return nil
+}
+
+// Jobs return the status of the scheduled jobs, sorted by name.
+func (s *Server) Jobs() []JobStatus {
+	s.jobs.mu.RLock()
+	defer s.jobs.mu.RUnlock()
+
+	ret := make([]JobStatus, 0, len(s.jobs.jobs))
+	for _, jb := range s.jobs.jobs {
+		ret = append(ret, jb.status)
+	}
+
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+
+	return ret
+}
+
+// schedule trigger the jb job runs until the server stop.
+func (s *Server) schedule(jb *job) {
+	defer s.wg.Done()
+
+	lg := s.Logger(LogWorkers)
+
+	lg.Debug("job: scheduled", slog.String("name", jb.Name))
+	defer lg.Debug("job: done", slog.String("name", jb.Name))
+
+	for {
+		next := jb.Schedule.Next(time.Now())
+		if next.IsZero() {
+			lg.Warn("job: no next run", slog.String("name", jb.Name))
+
+			return
+		}
+
+		s.jobs.mu.Lock()
+		jb.status.NextRun = next
+		s.jobs.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-timer.C:
+			s.trigger(jb)
+		case <-s.ctx.Done():
+			timer.Stop()
+
+			return
+		}
+	}
+}
+
+// trigger start a jb job run, according to its overlap policy.
+func (s *Server) trigger(jb *job) {
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+
+	if jb.status.Running {
+		if jb.Overlap == OverlapQueue && !jb.pending {
+			jb.pending = true
+
+			return
+		}
+
+		jb.status.Skipped++
+		s.Logger(LogWorkers).Warn("job: still running, run skipped", slog.String("name", jb.Name))
+
+		return
+	}
+
+	jb.status.Running = true
+	s.wg.Add(1)
+
+	go s.runJob(jb)
+}
+
+// runJob run the jb job, and the queued run if any.
+func (s *Server) runJob(jb *job) {
+	defer s.wg.Done()
+
+	for {
+		ctx, cancel := s.ctx, context.CancelFunc(func() {})
+		if jb.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, jb.Timeout)
+		}
+
+		start := time.Now()
+
+		s.jobs.mu.Lock()
+		jb.status.LastRun = start
+		s.jobs.mu.Unlock()
+
+		e := runWorker(ctx, jb.Run)
+
+		cancel()
+
+		if e != nil {
+			s.Logger(LogWorkers).Error("job: failure", slog.String("name", jb.Name), slog.Any("error", e))
+		}
+
+		s.jobs.mu.Lock()
+		jb.status.Runs++
+		jb.status.LastDuration = time.Since(start).String()
+		jb.status.LastError = ""
+
+		if e != nil {
+			jb.status.LastError = e.Error()
+		}
+
+		if !jb.pending || s.ctx.Err() != nil {
+			jb.pending, jb.status.Running = false, false
+			s.jobs.mu.Unlock()
+
+			return
+		}
+
+		jb.pending = false
+		s.jobs.mu.Unlock()
+	}
+}
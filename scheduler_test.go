@@ -0,0 +1,147 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitJob(t *testing.T, s *Server, name string, cond func(JobStatus) bool) JobStatus {
+	t.Helper()
+
+	var js JobStatus
+
+	require.Eventually(t, func() bool {
+		for _, js = range s.Jobs() {
+			if js.Name == name && cond(js) {
+				return true
+			}
+		}
+
+		return false
+	}, 5*time.Second, 5*time.Millisecond, "job %q", name)
+
+	return js
+}
+
+func TestScheduleJob(t *testing.T) {
+	var (
+		ticks    atomic.Int32
+		canceled = make(chan struct{})
+		release  = make(chan struct{})
+		skip     = make(chan struct{})
+	)
+
+	s, e := InitServer(WithJobs(
+		Job{Name: "tick", Schedule: Every(5 * time.Millisecond), Run: func(context.Context) error {
+			if ticks.Add(1) == 2 {
+				return errors.New("tick failure")
+			}
+
+			return nil
+		}},
+		Job{Name: "skip", Schedule: Every(5 * time.Millisecond), Run: func(ctx context.Context) error {
+			select {
+			case <-skip:
+			case <-ctx.Done():
+			}
+
+			return nil
+		}},
+		Job{Name: "queue", Overlap: OverlapQueue, Schedule: Every(5 * time.Millisecond),
+			Run: func(ctx context.Context) error {
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+
+				return nil
+			}},
+		Job{Name: "long", Schedule: Every(time.Millisecond), Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			close(canceled)
+
+			return ctx.Err()
+		}},
+	))
+	require.Nil(t, e)
+
+	// the jobs aren't run until the server is served
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, s.Jobs())
+	assert.Zero(t, ticks.Load())
+
+	done := make(chan error, 1)
+
+	go func() { done <- s.Serve(context.Background(), Address{Addr: freeAddr(t)}) }()
+
+	waitJob(t, s, "tick", func(js JobStatus) bool { return js.Runs >= 1 })
+
+	require.ErrorIs(t, s.ScheduleJob(Job{Name: "tick", Schedule: Every(time.Second),
+		Run: func(context.Context) error { return nil }}), ErrJobExists)
+	require.NotNil(t, s.ScheduleJob(Job{Name: "bad", Overlap: "parallel", Schedule: Every(time.Second),
+		Run: func(context.Context) error { return nil }}))
+
+	t.Run("runs", func(t *testing.T) {
+		js := waitJob(t, s, "tick", func(js JobStatus) bool { return js.Runs >= 3 })
+		assert.Empty(t, js.LastError)
+		assert.False(t, js.LastRun.IsZero())
+		assert.NotEmpty(t, js.LastDuration)
+		assert.True(t, js.NextRun.After(js.LastRun))
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		waitJob(t, s, "skip", func(js JobStatus) bool { return js.Running && js.Skipped >= 3 })
+		waitJob(t, s, "queue", func(js JobStatus) bool { return js.Running && js.Skipped >= 3 })
+
+		// the queued run is started once the running one end
+		release <- struct{}{}
+
+		waitJob(t, s, "queue", func(js JobStatus) bool { return js.Runs == 1 && js.Running })
+	})
+
+	t.Run("endpoint", func(t *testing.T) {
+		var (
+			fc   = requestRouter(t, s, http.MethodGet, "/jobs")
+			jobs []JobStatus
+		)
+
+		assert.Equal(t, http.StatusOK, fc.Response.StatusCode())
+		require.Nil(t, json.Unmarshal(fc.Response.Body(), &jobs))
+		require.Len(t, jobs, 4)
+		assert.Equal(t, "long", jobs[0].Name)
+		assert.Equal(t, OverlapQueue, jobs[1].Overlap)
+	})
+
+	require.Nil(t, s.Shutdown())
+	require.Nil(t, <-done)
+
+	stopped := make(chan struct{})
+
+	go func() {
+		s.WaitForStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("jobs not stopped")
+	}
+
+	<-canceled
+
+	for _, js := range s.Jobs() {
+		assert.False(t, js.Running, js.Name)
+	}
+
+	assert.ErrorIs(t, s.ScheduleJob(Job{Name: "late", Schedule: Every(time.Second),
+		Run: func(context.Context) error { return nil }}), ErrServerStopped)
+}
@@ -0,0 +1,175 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+)
+
+var (
+	// ErrAddressInUse is returned when the listening address is already bound.
+	ErrAddressInUse = errors.New("address already in use")
+
+	// ErrPermission is returned when binding the address or the unix socket
+	// isn't permitted.
+	ErrPermission = errors.New("permission denied")
+
+	// ErrInvalidAddress is returned when the address cannot be listened on.
+	ErrInvalidAddress = errors.New("invalid address")
+
+	// ErrTLSCertificate is returned when the tls certificate or key cannot be loaded.
+	ErrTLSCertificate = errors.New("invalid tls certificate")
+
+	// ErrTLSCA is returned when the tls client CA cannot be loaded.
+	ErrTLSCA = errors.New("invalid tls ca")
+
+	// ErrHTTP2RequireTLS is returned when a plain endpoint is started with
+	// the http2 support enabled.
+	ErrHTTP2RequireTLS = errors.New("https endpoints required with http2")
+)
+
+// fatal box the first fatal error of the server, see Serve.
+type fatal struct{ err error }
+
+// ListenError is returned when a listener cannot be started or failed
+// while serving. Use errors.Is against the Err* sentinels to get its kind.
+type ListenError struct {
+	// Kind hold one of the Err* sentinels, nil if the failure isn't classified.
+	Kind error
+
+	// Err hold the underlying error.
+	Err error
+
+	Listener
+}
+
+// Error implement the error interface.
+func (e *ListenError) Error() string {
+	if e.Kind == nil || errors.Is(e.Err, e.Kind) {
+		return fmt.Sprintf("listening on %q: %v", e.Addr, e.Err)
+	}
+
+	return fmt.Sprintf("listening on %q: %v: %v", e.Addr, e.Kind, e.Err)
+}
+
+// Unwrap allow errors.Is and errors.As to reach the kind and the underlying error.
+func (e *ListenError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// listenError wrap the e error of the l listener in a classified ListenError.
+func listenError(l Listener, e error) *ListenError {
+	var (
+		le    = &ListenError{Listener: l, Err: e}
+		aerr  *net.AddrError
+		dnerr *net.DNSError
+	)
+
+	switch {
+	case errors.Is(e, ErrAddressInUse):
+		le.Kind = ErrAddressInUse
+	case errors.Is(e, ErrInvalidAddress):
+		le.Kind = ErrInvalidAddress
+	case errors.Is(e, tls.ErrLoadCert):
+		le.Kind = ErrTLSCertificate
+	case errors.Is(e, tls.ErrLoadCA), errors.Is(e, tls.ErrParseUserCA):
+		le.Kind = ErrTLSCA
+	case errors.Is(e, syscall.EADDRINUSE):
+		le.Kind = ErrAddressInUse
+	case errors.Is(e, os.ErrPermission):
+		le.Kind = ErrPermission
+	case errors.As(e, &aerr), errors.As(e, &dnerr):
+		le.Kind = ErrInvalidAddress
+	}
+
+	return le
+}
+
+// Serve start listening on the addrs addresses, and the admin one if any
+// (see WithAdminAddress), and block until the ctx context is canceled, the
+// server is shutdown or a listener fail.
+//
+// Bind failures are reported synchronously: the already started listeners
+// are stopped and a *ListenError is returned. Once running, the first fatal
+// listener error, or *WorkerError of a critical worker (see StartWorker), is
+// returned. In both case, it's up to the caller to decide whether to exit.
+//
+// The jobs of WithJobs are scheduled once the listeners are started.
+func (s *Server) Serve(ctx context.Context, addrs ...Address) error {
+	defer s.WaitForStop()
+
+	if s.meta.admin != nil {
+		addrs = append(addrs[:len(addrs):len(addrs)], *s.meta.admin)
+	}
+
+	s.startBatch()
+
+	for i := range addrs {
+		if _, e := s.startAddress(addrs[i]); e != nil {
+			s.closeInherited()
+			s.endBatch(false)
+			s.abort(e)
+
+			return e
+		}
+	}
+
+	s.closeInherited()
+	s.endBatch(true)
+	s.upgradeReady()
+	s.startJobs()
+
+	select {
+	case <-ctx.Done():
+	case <-s.ctx.Done():
+		if s.getFailure() == nil {
+			// shutdown from elsewhere
+			return nil
+		}
+	}
+
+	if e := s.Shutdown(); e != nil {
+		s.slog.Error("shutdown", slog.Any("error", e))
+	}
+
+	return s.getFailure()
+}
+
+// abort stop the running listeners after the e start failure, saved as the
+// server failure.
+func (s *Server) abort(e error) {
+	s.slog.Error("starting server", slog.Any("error", e))
+	s.setFailure(e)
+
+	if se := s.Shutdown(); se != nil {
+		s.slog.Error("shutdown", slog.Any("error", se))
+	}
+}
+
+// fail save the e error of the l listener if it's the first fatal one.
+func (s *Server) fail(l Listener, e error) {
+	s.slog.Error("listener failure", slog.String("address", l.Addr), slog.Any("error", e))
+	s.setFailure(listenError(l, e))
+}
+
+// setFailure save the e error if it's the first fatal one.
+func (s *Server) setFailure(e error) { s.failure.CompareAndSwap(nil, &fatal{e}) }
+
+// getFailure return the first fatal error, if any.
+func (s *Server) getFailure() error {
+	if f := s.failure.Load(); f != nil {
+		return f.err
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeErrors(t *testing.T) {
+	busy, e := net.Listen("tcp4", "127.0.0.1:0")
+	require.Nil(t, e)
+
+	defer busy.Close()
+
+	badCA := filepath.Join(t.TempDir(), "ca.pem")
+	require.Nil(t, os.WriteFile(badCA, []byte("garbage"), 0o600))
+
+	tests := map[string]struct {
+		opts  []Option
+		addrs Addresses
+		kind  error
+	}{
+		"invalid address": {
+			addrs: Addresses{{Name: "empty"}},
+			kind:  ErrInvalidAddress,
+		},
+		"address in use": {
+			addrs: Addresses{{Addr: busy.Addr().String()}},
+			kind:  ErrAddressInUse,
+		},
+		"partial start": {
+			addrs: Addresses{{Addr: freeAddr(t)}, {Addr: busy.Addr().String()}},
+			kind:  ErrAddressInUse,
+		},
+		"bad certificate": {
+			addrs: Addresses{{Addr: freeAddr(t), TLS: &tls.Config{Cert: "missing", Key: "missing"}}},
+			kind:  ErrTLSCertificate,
+		},
+		"bad ca": {
+			addrs: Addresses{{Addr: freeAddr(t), TLS: &tls.Config{
+				Cert: "./example/ssl.crt", Key: "./example/ssl.key", Ca: badCA,
+			}}},
+			kind: ErrTLSCA,
+		},
+		"http2 without tls": {
+			opts:  []Option{WithHTTP2()},
+			addrs: Addresses{{Addr: freeAddr(t)}},
+			kind:  ErrHTTP2RequireTLS,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, e := InitServer(test.opts...)
+			require.Nil(t, e)
+
+			e = s.Serve(context.Background(), test.addrs...)
+			require.NotNil(t, e)
+			assert.True(t, errors.Is(e, test.kind), e.Error())
+
+			var le *ListenError
+			require.True(t, errors.As(e, &le))
+			assert.Equal(t, test.addrs[len(test.addrs)-1].Addr, le.Addr)
+
+			assert.NotNil(t, s.GetContext().Err())
+			assert.Empty(t, s.Listeners())
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	var (
+		addr        = freeAddr(t)
+		ctx, cancel = context.WithCancel(context.Background())
+		done        = make(chan error)
+	)
+
+	go func() { done <- s.Serve(ctx, Address{Addr: addr}) }()
+
+	require.Eventually(t, func() bool { return len(s.Listeners()) == 1 },
+		time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	require.Nil(t, <-done)
+	assert.Empty(t, s.Listeners())
+}
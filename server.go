@@ -2,24 +2,21 @@ package webfmwk
 
 import (
 	"context"
+	fmtls "crypto/tls"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 
 	"github.com/burgesQ/webfmwk/v6/tls"
 	fasthttp2 "github.com/dgrr/http2"
 	"github.com/lab259/cors"
-	"github.com/valyala/fasthttp"
-	// "golang.org/x/sync/errgroup"
-)
-
-const (
-	exitTLSConfigFailure   = 2
-	exitTLSListenerFailure = 3
 )
 
 type (
@@ -30,58 +27,78 @@ type (
 		wg       *sync.WaitGroup
 		launcher WorkerLauncher
 		// log      log.Log
-		slog    *slog.Logger
-		isReady chan bool
-		meta    serverMeta
+		slog      *slog.Logger
+		isReady   chan bool
+		conns     *connTracker
+		health    *HealthRegistry
+		loggers   *loggers
+		ready     *readiness
+		workers   workers
+		jobs      scheduler
+		listeners listeners
+		reconfMu  sync.Mutex
+		inherited inherited
+		meta      serverMeta
+		failure   atomic.Pointer[fatal]
+		inFlight  atomic.Int64
+		draining  atomic.Bool
 	}
 )
 
-var (
-	// TODO: use sync.Pool
-	// poolOfServers hold all the http(s) server to properly shut them down
-	poolOfServers []*fasthttp.Server
-	poolMu        sync.Mutex
-)
-
 // Run allow to launch multiple server from a single call.
 // It take an va arg list of Address as argument.
-// The method wait for the server to end via a call to WaitAndStop.
+// The method wait for the server to end. Errors are only logged,
+// use Serve to handle them.
 func (s *Server) Run(addrs ...Address) {
-	defer s.WaitForStop()
+	if e := s.Serve(context.Background(), addrs...); e != nil {
+		s.slog.Error("running server", slog.Any("error", e))
+	}
+}
 
-	for i := range addrs {
-		addr := addrs[i]
-		if !addr.IsOk() {
-			s.GetStructuredLogger().Error("invalid format", "address", addr)
+// startAddress start the addr listener, returning a *ListenError on failure.
+func (s *Server) startAddress(addr Address) (*listener, error) {
+	l := Listener{Name: addr.GetName(), Addr: addr.GetAddr(), Admin: s.isAdmin(addr)}
 
-			continue
-		}
+	if nl, e := s.upgradeListener(addr.GetAddr()); e != nil {
+		return nil, listenError(l, e)
+	} else if nl != nil {
+		s.slog.Info("serving upgraded socket", "name", addr.GetName(), "address", addr.GetAddr())
+
+		return s.serveAddress(addr, nl)
+	}
+
+	if nl, e := s.inheritedListener(addr.GetName()); e != nil {
+		return nil, listenError(l, e)
+	} else if nl != nil {
+		s.slog.Info("serving inherited socket", "name", addr.GetName(), "address", nl.Addr().String())
 
-		switch cfg := addr.GetTLS(); {
-		case cfg != nil && !cfg.Empty():
-			s.GetStructuredLogger().Info("starting https server",
-				"name", addr.GetName(), "address", "https://"+addr.GetAddr())
-			s.StartTLS(addr.GetAddr(), cfg)
+		return s.serveAddress(addr, nl)
+	}
 
-		case addr.IsUnixPath():
-			s.GetStructuredLogger().Info("starting unix socket server",
-				"name", addr.GetName(), "path", addr.GetAddr())
+	if e := addr.Validate(); e != nil {
+		return nil, &ListenError{Kind: ErrInvalidAddress, Err: e, Listener: l}
+	}
 
-			if e := s.StartUnixSocket(addr.GetAddr()); e != nil {
-				s.slog.Error("starting server", slog.Any("error", e))
+	ep, _ := addr.Endpoint()
 
-				s.cancel()
+	switch cfg := addr.GetTLS(); {
+	case cfg != nil && !cfg.Empty():
+		s.GetStructuredLogger().Info("starting https server",
+			"name", addr.GetName(), "address", addr.GetAddr())
 
-				return
-			}
+		return s.startTLS(l, ep, cfg)
 
-			// continue
+	case ep.IsUnix():
+		s.GetStructuredLogger().Info("starting unix socket server",
+			"name", addr.GetName(), "path", addr.GetAddr())
 
-		default:
-			s.GetStructuredLogger().Info("starting http server",
-				"name", addr.GetName(), "address", "http://"+addr.GetAddr())
-			s.Start(addr.GetAddr())
-		}
+		return s.startUnixSocket(l, ep)
+
+	default:
+		s.GetStructuredLogger().Info("starting http server",
+			"name", addr.GetName(), "address", addr.GetAddr())
+
+		return s.start(l, ep)
 	}
 }
 
@@ -89,21 +106,27 @@ func (s *Server) Run(addrs ...Address) {
 // Global methods
 //
 
-// Shututdown terminate all running servers.
+// Shutdown terminate the running servers of all the Server instances.
+//
+// Deprecated: use Server.Shutdown, which only stop the listeners owned by
+// the instance.
 func Shutdown() error {
 	poolMu.Lock()
-	defer poolMu.Unlock()
+	servers := make([]*Server, 0, len(poolOfServers))
+
+	for s := range poolOfServers {
+		servers = append(servers, s)
+	}
+	poolMu.Unlock()
 
 	var senti error
 
-	for i, server := range poolOfServers {
-		if e := server.Shutdown(); e != nil {
-			senti = fmt.Errorf("shutdowning server %d : %w", i, e)
+	for _, s := range servers {
+		if e := s.Shutdown(); e != nil {
+			senti = e
 		}
 	}
 
-	poolOfServers = nil
-
 	return senti
 }
 
@@ -116,89 +139,183 @@ func Shutdown() error {
 //
 
 // Start expose an server to an HTTP endpoint.
+// On failure, the error is logged and the server context canceled.
 func (s *Server) Start(addr string) {
-	if s.meta.http2 {
-		s.slog.Warn("https endpoints required with http2, skipping", slog.String("address", addr))
+	if _, e := s.startEndpoint(Listener{Addr: addr}, s.start); e != nil {
+		s.slog.Error("http server", slog.String("address", addr), slog.Any("error", e))
+		s.cancel()
+	}
+}
 
-		return
+// startEndpoint parse the l address and start it via the fn start function.
+func (s *Server) startEndpoint(l Listener, fn func(Listener, Endpoint) (*listener, error)) (*listener, error) {
+	ep, e := ParseEndpoint(l.Addr)
+	if e != nil {
+		return nil, &ListenError{Kind: ErrInvalidAddress, Err: e, Listener: l}
+	}
+
+	return fn(l, ep)
+}
+
+func (s *Server) start(l Listener, ep Endpoint) (*listener, error) {
+	if s.meta.http2 {
+		return nil, &ListenError{Kind: ErrHTTP2RequireTLS, Err: errors.New("plain http listener"), Listener: l}
 	}
 
 	s.internalHandler()
 
-	started := make(chan struct{})
+	nl, e := ep.Listen(s.ctx)
+	if e != nil {
+		return nil, listenError(l, e)
+	}
+
+	return s.servePlain(l, nl), nil
+}
+
+// servePlain serve the nl listener without TLS.
+func (s *Server) servePlain(l Listener, nl net.Listener) *listener {
+	var (
+		ln   = s.internalInit(l, nil, nl)
+		name = "http server"
+	)
 
-	s.launcher.Start(func() {
-		s.slog.Debug("http server: starting", slog.String("address", addr))
+	ln.raw = nl
 
-		go s.pollPingEndpoint(addr)
+	if l.Unix {
+		name = "unix socket server"
+	}
 
-		close(started)
-		if e := s.internalInit(addr).ListenAndServe(addr); e != nil {
-			s.slog.Error("http server", slog.String("address", addr), slog.Any("error", e))
-		}
+	s.serve(ln, func() error {
+		s.slog.Debug(name+": starting", slog.String("address", l.Addr))
+		defer s.slog.Info(name+": done", slog.String("address", l.Addr))
 
-		s.slog.Info("http server: done", slog.String("address", addr))
+		return ln.server.Serve(ln.nl)
 	})
 
-	<-started
+	return ln
 }
 
+// StartUnixSocket expose an server to an unix socket.
+// The path may omit the unix:// prefix. A stale socket file is removed, and
+// the socket file is removed once the server stop.
 func (s *Server) StartUnixSocket(path string) error {
+	if !strings.HasPrefix(path, _unixSocketPrefix) {
+		path = _unixSocketPrefix + path
+	}
+
+	_, e := s.startEndpoint(Listener{Addr: path}, s.startUnixSocket)
+
+	return e
+}
+
+func (s *Server) startUnixSocket(l Listener, ep Endpoint) (*listener, error) {
 	s.internalHandler()
 
-	server := s.internalInit(path)
+	l.Unix = true
 
-	s.launcher.Start(func() {
-		s.slog.Debug("unix socket server: starting", slog.String("path", path))
-		defer s.slog.Info("unix socket server: done", slog.String("path", path))
+	if e := removeStaleSocket(ep.Addr); e != nil {
+		return nil, listenError(l, e)
+	}
 
-		if e := server.ListenAndServeUNIX(
-			strings.TrimPrefix(path, _unixSocketPrefix),
-			os.ModeSocket); e != nil {
-			s.slog.Error("unix socket server", slog.String("path", path), slog.Any("error", e))
+	if !ep.hasSocketFile() {
+		nl, e := ep.Listen(s.ctx)
+		if e != nil {
+			return nil, listenError(l, e)
 		}
-	})
 
-	return nil
+		return s.servePlain(l, nl), nil
+	}
+
+	nl, e := listenSocketFile(s.ctx, ep)
+	if e != nil {
+		return nil, listenError(l, e)
+	}
+
+	return s.servePlain(l, nl), nil
 }
 
 // StartTLS expose an https server.
 // The server may have mTLS and/or http2 capabilities.
+// On failure, the error is logged and the server context canceled.
 func (s *Server) StartTLS(addr string, cfg tls.IConfig) {
+	if _, e := s.startEndpoint(Listener{Addr: addr}, func(l Listener, ep Endpoint) (*listener, error) {
+		return s.startTLS(l, ep, cfg)
+	}); e != nil {
+		s.slog.Error("starting tls server", slog.Any("error", e))
+		s.cancel()
+	}
+}
+
+func (s *Server) startTLS(l Listener, ep Endpoint, cfg tls.IConfig) (*listener, error) {
 	s.internalHandler()
 
-	tlsCfg, err := tls.GetTLSCfg(cfg, s.meta.http2)
-	if err != nil {
-		s.slog.Error("loading tls config", slog.Any("error", err))
-		os.Exit(exitTLSConfigFailure)
+	l.HTTP2 = s.meta.http2 || ep.HTTP2
+
+	rl, e := tls.NewReloader(cfg, s.Logger(LogTLS), l.HTTP2)
+	if e != nil {
+		return nil, listenError(l, e)
 	}
 
-	listner, err := tls.LoadListner(addr, tlsCfg)
-	if err != nil {
-		s.slog.Error("loading tls listener", slog.Any("error", err))
-		os.Exit(exitTLSListenerFailure)
+	nl, e := ep.Listen(s.ctx)
+	if e != nil {
+		return nil, listenError(l, e)
 	}
 
-	server := s.internalInit(addr)
+	return s.serveTLS(l, cfg, rl, nl), nil
+}
 
-	if s.meta.http2 {
+// serveTLS serve the nl listener over TLS using the rl material, with the
+// http2 support if l.HTTP2 is set.
+func (s *Server) serveTLS(l Listener, cfg tls.IConfig, rl *tls.Reloader, nl net.Listener) *listener {
+	l.TLS = true
+	ln := s.internalInit(l, cfg, s.observeTLS(l, fmtls.NewListener(nl, rl.Config())))
+	ln.reloader, ln.raw = rl, nl
+
+	if l.HTTP2 {
 		s.slog.Info("loading http2 support")
-		fasthttp2.ConfigureServer(server, fasthttp2.ServerConfig{Debug: true})
+		fasthttp2.ConfigureServer(ln.server, fasthttp2.ServerConfig{Debug: true})
 	}
 
-	so2 := sOr2(s.meta.http2)
+	s.watchTLS(ln)
 
-	s.launcher.Start(func() {
-		s.slog.Debug(fmt.Sprintf("%s server: starting", so2), slog.String("address", addr))
-		defer s.slog.Info(fmt.Sprintf("%s server: done", so2), slog.String("address", addr))
+	so2 := sOr2(l.HTTP2)
 
-		go s.pollPingEndpoint(addr, cfg)
+	s.serve(ln, func() error {
+		s.slog.Debug(so2+" server: starting", slog.String("address", l.Addr))
+		defer s.slog.Info(so2+" server: done", slog.String("address", l.Addr))
 
-		if e := server.Serve(listner); e != nil {
-			s.slog.Error(fmt.Sprintf("%s server", so2),
-				slog.String("address", addr), slog.Any("error", e))
-		}
+		return ln.server.Serve(ln.nl)
 	})
+
+	return ln
+}
+
+// serve run the fn listener loop in a server worker. Like for the
+// WorkerLauncher jobs, the server context is canceled once fn return,
+// unless the listener was stopped on its own (see Reconfigure).
+// The fn error is saved as the server failure, see Serve.
+// The listener being bound, it's reported as ready, see Ready.
+func (s *Server) serve(ln *listener, fn func() error) {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		e := fn()
+		ln.cancel()
+
+		if ln.detached.Load() {
+			return
+		}
+
+		if e != nil {
+			s.fail(ln.Listener, e)
+		}
+
+		s.cancel()
+	}()
+
+	s.markReady(ln.Listener)
 }
 
 func sOr2(http2 bool) string {
@@ -216,22 +333,28 @@ func (s *Server) ShutdownAndWait() error {
 	return s.Shutdown()
 }
 
-// Shutdown call the framework shutdown to stop all running server.
+// Shutdown call ShutdownWithContext to stop all running server.
+// The drain phase is bounded by the SetShutdownTimeout value, if any.
 func (s *Server) Shutdown() error {
-	s.cancel()
+	ctx, cancel := s.shutdownContext()
+	defer cancel()
 
-	return Shutdown()
+	return s.ShutdownWithContext(ctx)
+}
+
+// shutdownContext return a context bounded by the SetShutdownTimeout value, if any.
+func (s *Server) shutdownContext() (context.Context, context.CancelFunc) {
+	if s.meta.shutdownTimeout > 0 {
+		return context.WithTimeout(context.Background(), s.meta.shutdownTimeout)
+	}
+
+	return context.WithCancel(context.Background())
 }
 
 // WaitForStop wait for all servers to terminate.
 // Use of a sync.waitGroup to properly wait all running servers.
 func (s *Server) WaitForStop() {
 	s.wg.Wait()
-
-	// g := errgroup.Group{}
-	// if err := g.Wait(); err != nil {
-	// 	return nil, err
-	// }
 }
 
 // DumpRoutes dump the API endpoints using the server logger.
@@ -253,15 +376,22 @@ func (flg *FastLogger) Printf(msg string, keys ...any) {
 	flg.Info(fmt.Sprintf(msg, keys...))
 }
 
-// Initialize a http.Server struct. Save the server in the pool of workers.
-func (s *Server) internalInit(addr string) *fasthttp.Server {
+// Initialize a http.Server struct serving the nl listener. Save the server in
+// the server listeners.
+func (s *Server) internalInit(l Listener, cfg tls.IConfig, nl net.Listener) *listener {
 	var (
-		worker = s.meta.toServer(addr)
-		router = s.GetRouter()
+		worker = s.meta.toServer(l.Addr)
+		router = Tern(l.Admin, s.GetAdminRouter, s.GetRouter)
+		ln     = &listener{
+			server: worker, nl: &onceCloseListener{Listener: nl},
+			conns: newConnTracker(), cfg: cfg, Listener: l,
+		}
 	)
 
+	ln.ctx, ln.cancel = context.WithCancel(s.ctx)
+
 	// register CORS handler - note that it should be the first one
-	if s.meta.cors {
+	if s.meta.cors && !l.Admin {
 		worker.Handler = cors.New(cors.Options{
 			AllowedOrigins:   []string{"*"},
 			AllowedHeaders:   []string{"X-Requested-With", "Content-Type"},
@@ -273,39 +403,49 @@ func (s *Server) internalInit(addr string) *fasthttp.Server {
 		worker.Handler = router.Handler
 	}
 
+	if lr := s.limitsRouter(); lr != nil && !l.Admin {
+		worker.HeaderReceived = headerReceived(lr)
+	}
+
 	worker.Logger = &FastLogger{s.slog}
+	worker.ConnState = s.connState(ln)
 
 	// save the server
-	poolMu.Lock()
-	defer poolMu.Unlock()
-
-	poolOfServers = append(poolOfServers, worker)
-
-	s.slog.Debug("[+] server ", slog.String("address", addr), slog.Int("total", len(poolOfServers)))
-
-	return worker
-}
+	total := s.registerListener(ln)
 
-func concatAddr(addr, prefix string) string {
-	if len(addr) > 1 && addr[0] == ':' {
-		return "http://127.0.0.1" + addr + prefix + _pingEndpoint
-	} else if strings.HasPrefix(addr, "127.0.0.1") {
-		return "http://" + addr + prefix + _pingEndpoint
-	}
+	s.slog.Debug("[+] server ", slog.String("address", l.Addr), slog.Int("total", total))
 
-	return addr + prefix + _pingEndpoint
+	return ln
 }
 
-// launch the ctrl+c job if needed.
+// launch the ctrl+c and upgrade jobs if needed, and the queued scheduled
+// jobs.
 func (s *Server) internalHandler() {
-	if s.meta.ctrlc && !s.meta.ctrlcStarted {
-		s.launcher.Start(func() {
-			s.slog.Debug("exit handler: starting")
-			s.exitHandler(os.Interrupt, syscall.SIGHUP)
-			s.slog.Info("exit handler: done")
+	s.startJobs()
+
+	if s.meta.ctrlc {
+		s.meta.ctrlcOnce.Do(func() {
+			s.launcher.Start(func() {
+				s.slog.Debug("exit handler: starting")
+
+				// SIGHUP trigger a tls reload if enabled
+				if s.meta.tlsSignal {
+					s.exitHandler(os.Interrupt)
+				} else {
+					s.exitHandler(os.Interrupt, syscall.SIGHUP)
+				}
+
+				s.slog.Info("exit handler: done")
+			})
 		})
+	}
+
+	if s.meta.tlsSignal {
+		s.meta.sighupOnce.Do(func() { s.launcher.Start(s.reloadHandler) })
+	}
 
-		s.meta.ctrlcStarted = true
+	if s.meta.upgradeSignal != nil {
+		s.meta.upgradeOnce.Do(func() { s.launcher.Start(s.upgradeHandler) })
 	}
 }
 
@@ -341,6 +481,7 @@ func (s *Server) exitHandler(sig ...os.Signal) {
 func (s *Server) GetStructuredLogger() *slog.Logger { return s.slog }
 
 // GetLauncher return a pointer to the internal workerLauncher.
+// See StartWorker for named and supervised workers.
 func (s *Server) GetLauncher() WorkerLauncher { return s.launcher }
 
 // GetContext return the context.Context used.
@@ -349,7 +490,8 @@ func (s *Server) GetContext() context.Context { return s.ctx }
 // GetContext return the server' context cancel func.
 func (s *Server) GetCancel() context.CancelFunc { return s.cancel }
 
-// IsReady return the channel on which `true` is send once the server is up.
+// IsReady return the channel on which `true` is send once the server is up,
+// see WaitReady.
 func (s *Server) IsReady() chan bool { return s.isReady }
 
 // AddHandlers register the Handler handlers. Handler are executed from the top most.
@@ -373,9 +515,10 @@ func (s *Server) setPrefix(prefix string) *Server {
 	return s
 }
 
-// RegisterLogger register the Log used.
+// RegisterLogger register the Log used, from which the named loggers derive.
 func (s *Server) registerStructuredLogger(slg *slog.Logger) *Server {
-	s.slog = slg
+	s.loggers.setBase(slg)
+	s.slog = s.loggers.logger(LogServer, false)
 
 	return s
 }
@@ -387,9 +530,7 @@ func (s *Server) enableCORS() *Server {
 	return s
 }
 
-// enableCheckIsUp add an /ping endpoint.
-// If used, once a server is started, the user can check weather the server is
-// up or not by reading the isReady channel vie the IsReady() method.
+// EnableCheckIsUp add an /ping endpoint.
 func (s *Server) EnableCheckIsUp() *Server {
 	s.meta.checkIsUp = true
 
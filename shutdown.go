@@ -0,0 +1,195 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+)
+
+// connTracker keep track of the open connections of the servers,
+// so they can be forcibly closed once the shutdown deadline is reached.
+type connTracker struct {
+	conns map[net.Conn]struct{}
+	mu    sync.Mutex
+}
+
+func newConnTracker() *connTracker {
+	return &connTracker{conns: make(map[net.Conn]struct{})}
+}
+
+// hook implement the fasthttp.Server.ConnState callback.
+func (ct *connTracker) hook(c net.Conn, state fasthttp.ConnState) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	switch state {
+	case fasthttp.StateNew:
+		ct.conns[c] = struct{}{}
+	case fasthttp.StateClosed, fasthttp.StateHijacked:
+		delete(ct.conns, c)
+	case fasthttp.StateActive, fasthttp.StateIdle:
+	}
+}
+
+// len return the number of open connections.
+func (ct *connTracker) len() int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	return len(ct.conns)
+}
+
+// closeAll close all the tracked connections and return the number of closed ones.
+func (ct *connTracker) closeAll() int {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	n := len(ct.conns)
+
+	for c := range ct.conns {
+		forceClose(c)
+		delete(ct.conns, c)
+	}
+
+	return n
+}
+
+// forceClose shut the c connection down without releasing its descriptor:
+// the pending reads and writes fail, and fasthttp close it once the
+// in-flight handler return, instead of panicking while setting the
+// deadlines of a closed connection.
+func forceClose(c net.Conn) {
+	nc := c
+
+	// unwrap the tls connections
+	for {
+		u, ok := nc.(interface{ NetConn() net.Conn })
+		if !ok {
+			break
+		}
+
+		nc = u.NetConn()
+	}
+
+	if hc, ok := nc.(interface {
+		CloseRead() error
+		CloseWrite() error
+	}); ok {
+		_, _ = hc.CloseRead(), hc.CloseWrite()
+
+		return
+	}
+
+	_ = c.Close()
+}
+
+// ShutdownWithContext gracefully stop the running servers. It work in phases:
+//   - flip the /ping and /readyz endpoints to 503
+//   - stop accepting new connections, but on the admin listener
+//   - wait for the in-flight requests to complete, until ctx is done
+//   - stop the admin listener, so the readiness probes see the drain
+//   - force close the remaining connections
+//
+// An error is returned if the deadline is reached before the servers were drained.
+func (s *Server) ShutdownWithContext(ctx context.Context) error {
+	var (
+		servers       = s.listeners.take()
+		public, admin = splitAdmin(servers)
+	)
+
+	defer s.cancel()
+	defer s.unregisterServer()
+
+	s.slog.Info("shutdown: flipping ping endpoint to 503")
+
+	if !s.draining.Swap(true) {
+		s.notify(_sdStopping)
+	}
+
+	s.slog.Info("shutdown: stop accepting connections", slog.Int("listeners", len(public)))
+
+	wait := shutdownListeners(ctx, public)
+
+	s.slog.Info("shutdown: waiting for in-flight requests",
+		slog.Int64("requests", s.inFlight.Load()),
+		slog.Int("connections", s.conns.len()))
+
+	senti := wait()
+
+	if len(admin) > 0 {
+		s.slog.Info("shutdown: stopping the admin listener")
+
+		if e := shutdownListeners(ctx, admin)(); e != nil {
+			senti = e
+		}
+	}
+
+	if errors.Is(senti, context.DeadlineExceeded) || errors.Is(senti, context.Canceled) {
+		s.slog.Warn("shutdown: deadline reached, force closing connections",
+			slog.Int64("requests", s.inFlight.Load()),
+			slog.Int("connections", s.conns.closeAll()))
+
+		return senti
+	}
+
+	s.slog.Info("shutdown: done")
+
+	return senti
+}
+
+// splitAdmin split the servers listeners between the public and the admin
+// ones.
+func splitAdmin(servers []*listener) ([]*listener, []*listener) {
+	var public, admin []*listener
+
+	for _, l := range servers {
+		if l.Admin {
+			admin = append(admin, l)
+		} else {
+			public = append(public, l)
+		}
+	}
+
+	return public, admin
+}
+
+// shutdownListeners stop the servers listeners concurrently, and return a
+// function waiting for them to be drained, returning the last error.
+func shutdownListeners(ctx context.Context, servers []*listener) func() error {
+	errs := make(chan error, len(servers))
+
+	for i := range servers {
+		go func(l *listener) {
+			// the listener may not be served yet
+			_ = l.nl.Close()
+
+			if e := l.server.ShutdownWithContext(ctx); e != nil {
+				errs <- fmt.Errorf("shutdowning server %q : %w", l.Addr, e)
+
+				return
+			}
+
+			errs <- nil
+		}(servers[i])
+	}
+
+	return func() error {
+		var senti error
+
+		for range servers {
+			if e := <-errs; e != nil {
+				senti = e
+			}
+		}
+
+		return senti
+	}
+}
+
+// IsDraining return true once the server started its shutdown sequence.
+func (s *Server) IsDraining() bool { return s.draining.Load() }
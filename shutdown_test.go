@@ -0,0 +1,176 @@
+package webfmwk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/gommon/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startSlowServer(t *testing.T, delay time.Duration) (*Server, string) {
+	t.Helper()
+
+	s, e := InitServer(CheckIsUp())
+	require.Nil(t, e)
+
+	s.GET("/slow", func(c Context) error {
+		time.Sleep(delay)
+
+		return c.JSONOk(_pong)
+	})
+
+	p, e := port.GetFree()
+	require.Nil(t, e)
+
+	addr := fmt.Sprintf(":%d", p)
+
+	go s.Start(addr)
+	<-s.isReady
+
+	return s, "http://127.0.0.1" + addr
+}
+
+func TestShutdownWithContext(t *testing.T) {
+	t.Run("drain in-flight requests", func(t *testing.T) {
+		s, uri := startSlowServer(t, 200*time.Millisecond)
+
+		done := make(chan int)
+
+		go func() {
+			resp, e := http.Get(uri + "/slow") //nolint:noctx
+			if e != nil {
+				done <- 0
+
+				return
+			}
+
+			resp.Body.Close()
+			done <- resp.StatusCode
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		require.Nil(t, s.ShutdownWithContext(ctx))
+		assert.Equal(t, http.StatusOK, <-done)
+		assert.True(t, s.IsDraining())
+		s.WaitForStop()
+	})
+
+	t.Run("force close after the deadline", func(t *testing.T) {
+		s, uri := startSlowServer(t, 2*time.Second)
+
+		done := make(chan error)
+
+		go func() {
+			resp, e := http.Get(uri + "/slow") //nolint:noctx
+			if e == nil {
+				resp.Body.Close()
+			}
+
+			done <- e
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, s.ShutdownWithContext(ctx), context.DeadlineExceeded)
+		assert.NotNil(t, <-done)
+		s.WaitForStop()
+
+		// wait for the handler to return on the force closed connection
+		time.Sleep(2 * time.Second)
+	})
+}
+
+func TestPingDraining(t *testing.T) {
+	s, e := InitServer(CheckIsUp())
+	require.Nil(t, e)
+
+	fc := requestRouter(t, s, GET, _pingEndpoint)
+	assert.Equal(t, http.StatusOK, fc.Response.StatusCode())
+
+	s.draining.Store(true)
+
+	fc = requestRouter(t, s, GET, _pingEndpoint)
+	assert.Equal(t, http.StatusServiceUnavailable, fc.Response.StatusCode())
+	assert.Equal(t, `{"ping":"draining"}`, string(fc.Response.Body()))
+}
+
+func TestShutdownAdminLast(t *testing.T) {
+	var (
+		public, admin = freeAddr(t), freeAddr(t)
+		started       = make(chan struct{})
+		release       = make(chan struct{})
+		slow          = make(chan int, 1)
+		stopped       = make(chan error, 1)
+	)
+
+	s, e := InitServer(WithAdminAddress(Address{Addr: admin}))
+	require.Nil(t, e)
+
+	s.GET("/slow", func(c Context) error {
+		close(started)
+		<-release
+
+		return c.JSONOk(_pong)
+	})
+
+	require.Nil(t, s.Reconfigure(Addresses{{Addr: public}, *s.meta.admin}).Err())
+
+	get := func(uri string) (int, error) {
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+		resp, e := client.Get(uri) //nolint:noctx
+		if e != nil {
+			return 0, e
+		}
+
+		resp.Body.Close()
+
+		return resp.StatusCode, nil
+	}
+
+	go func() {
+		code, _ := get("http://" + public + "/slow")
+		slow <- code
+	}()
+
+	<-started
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		stopped <- s.ShutdownWithContext(ctx)
+	}()
+
+	require.Eventually(t, s.IsDraining, time.Second, time.Millisecond)
+
+	t.Log("the public listener is closed while the admin one answer the probes")
+	require.Eventually(t, func() bool {
+		_, e := get("http://" + public + "/slow")
+
+		return e != nil
+	}, time.Second, 10*time.Millisecond)
+
+	code, e := get("http://" + admin + _readyzEndpoint)
+	require.Nil(t, e)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-slow)
+	require.Nil(t, <-stopped)
+
+	_, e = get("http://" + admin + _readyzEndpoint)
+	assert.NotNil(t, e)
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package webfmwk
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// reusePort is not supported.
+func reusePort(string, string, syscall.RawConn) error {
+	return errors.New("SO_REUSEPORT is not supported")
+}
+
+// setNonblock is a no-op, the listeners cannot be passed to a new process.
+func setNonblock(net.Listener) error { return nil }
@@ -0,0 +1,45 @@
+//go:build unix
+
+package webfmwk
+
+import (
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// reusePort set SO_REUSEPORT on the socket, see net.ListenConfig.Control.
+func reusePort(_, _ string, c syscall.RawConn) error {
+	var se error
+
+	if e := c.Control(func(fd uintptr) {
+		se = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+	}); e != nil {
+		return e
+	}
+
+	return se
+}
+
+// setNonblock put back the nl listener fd in non blocking mode, the passing
+// of its duplicate to a new process clearing the flag shared by both.
+func setNonblock(nl net.Listener) error {
+	sc, ok := nl.(syscall.Conn)
+	if !ok {
+		return nil
+	}
+
+	rc, e := sc.SyscallConn()
+	if e != nil {
+		return e
+	}
+
+	var se error
+
+	if e := rc.Control(func(fd uintptr) { se = syscall.SetNonblock(int(fd), true) }); e != nil {
+		return e
+	}
+
+	return se
+}
@@ -7,8 +7,16 @@ import (
 )
 
 // LoadTLSListener return a tls listner ready for mTLS and/or http2.
+// The ipv6 addresses are bound dual-stack, the other on ipv4 only.
 func LoadListner(addr string, cfg *tls.Config) (net.Listener, error) {
-	listner, e := net.Listen("tcp4", addr)
+	network := "tcp4"
+	if host, _, e := net.SplitHostPort(addr); e == nil {
+		if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+			network = "tcp"
+		}
+	}
+
+	listner, e := net.Listen(network, addr)
 	if e != nil {
 		return nil, fmt.Errorf("creating tls listner: %w", e)
 	}
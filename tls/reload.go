@@ -0,0 +1,231 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// Reloader hold the TLS material (certificate and client CA) loaded from
+	// an IConfig. The material can be reloaded on demand or when the files
+	// change, or loaded from another IConfig via Swap, and is swapped
+	// atomically: the handshakes in progress keep the previous one. On
+	// failure, the previous material is kept.
+	Reloader struct {
+		icfg    IConfig
+		log     *slog.Logger
+		current atomic.Pointer[material]
+		stamps  map[string]stamp
+		http2   bool
+		mu      sync.Mutex
+	}
+
+	// material hold a loaded TLS material and its client auth settings.
+	material struct {
+		cert     *tls.Certificate
+		pool     *x509.CertPool
+		level    Level
+		insecure bool
+	}
+
+	// stamp identify a file version.
+	stamp struct {
+		mod  time.Time
+		size int64
+	}
+)
+
+// NewReloader load the icfg TLS material, returning an error if it's invalid.
+// Optional support for http can be specified via the http2 variadic argument.
+func NewReloader(icfg IConfig, log *slog.Logger, http2 ...bool) (*Reloader, error) {
+	r := &Reloader{
+		icfg:   icfg,
+		log:    log,
+		stamps: make(map[string]stamp),
+		http2:  len(http2) > 0 && http2[0],
+	}
+
+	r.changed()
+
+	m, e := load(icfg)
+	if e != nil {
+		return nil, e
+	}
+
+	r.current.Store(m)
+
+	return r, nil
+}
+
+// Config return a tls config serving the current material, ready for mTLS.
+// The client auth settings follow the material, see Swap.
+func (r *Reloader) Config() *tls.Config {
+	var (
+		cfg = getBaseTLSCfg(nil, r.http2)
+		cur = r.current.Load()
+	)
+
+	cfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return r.current.Load().cert, nil
+	}
+
+	cfg.ClientAuth = tls.NoClientCert
+	if !cur.insecure {
+		cfg.ClientAuth, cfg.ClientCAs = cur.level.STD(), cur.pool
+	}
+
+	cfg.GetConfigForClient = func(hi *tls.ClientHelloInfo) (*tls.Config, error) {
+		m := r.current.Load()
+
+		if m.insecure {
+			ret := getBaseTLSCfg(m.cert, r.http2)
+			ret.ClientAuth = tls.NoClientCert
+
+			return ret, nil
+		}
+
+		return wrapGetConfigForClient(m.cert, m.pool, m.level, r.http2)(hi)
+	}
+
+	return cfg
+}
+
+// Certificate return the current certificate.
+func (r *Reloader) Certificate() *tls.Certificate {
+	return r.current.Load().cert
+}
+
+// Reload load the material from the files. On failure the previous
+// material is kept and the error returned.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.store(r.icfg)
+}
+
+// Swap load the material from the icfg config, used from then on by Reload
+// and Watch. On failure the previous material and config are kept and the
+// error returned.
+func (r *Reloader) Swap(icfg IConfig) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e := r.store(icfg); e != nil {
+		return e
+	}
+
+	r.icfg, r.stamps = icfg, make(map[string]stamp)
+	r.refresh()
+
+	return nil
+}
+
+// store load the icfg material and swap it with the current one.
+// The lock must be held.
+func (r *Reloader) store(icfg IConfig) error {
+	m, e := load(icfg)
+	if e != nil {
+		r.log.Error("tls: reload failed, keeping the previous certificate",
+			slog.String("cert", icfg.GetCert()), slog.Any("error", e))
+
+		return e
+	}
+
+	r.current.Store(m)
+
+	args := []any{slog.String("cert", icfg.GetCert())}
+	if len(m.cert.Certificate) > 0 {
+		if leaf, e := x509.ParseCertificate(m.cert.Certificate[0]); e == nil {
+			args = append(args, slog.Time("not_after", leaf.NotAfter))
+		}
+	}
+
+	r.log.Info("tls: certificate reloaded", args...)
+
+	return nil
+}
+
+// Watch poll the cert, key and ca files every interval, reloading the
+// material when one of them changed. It return once ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.changed() {
+				_ = r.Reload()
+			}
+		}
+	}
+}
+
+// changed refresh the files stamp, returning true if one of them changed.
+func (r *Reloader) changed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.refresh()
+}
+
+// refresh refresh the files stamp, returning true if one of them changed.
+// The lock must be held.
+func (r *Reloader) refresh() bool {
+	ret := false
+
+	for _, p := range []string{r.icfg.GetCert(), r.icfg.GetKey(), r.icfg.GetCa()} {
+		if p == "" {
+			continue
+		}
+
+		var st stamp
+		if fi, e := os.Stat(p); e == nil {
+			st = stamp{mod: fi.ModTime(), size: fi.Size()}
+		}
+
+		if prev, ok := r.stamps[p]; !ok || !prev.mod.Equal(st.mod) || prev.size != st.size {
+			r.stamps[p], ret = st, true
+		}
+	}
+
+	return ret
+}
+
+// load read the icfg material from the files.
+func load(icfg IConfig) (*material, error) {
+	cert, e := tls.LoadX509KeyPair(icfg.GetCert(), icfg.GetKey())
+	if e != nil {
+		return nil, fmt.Errorf("%w [%s] and key [%s]: %w",
+			ErrLoadCert, icfg.GetCert(), icfg.GetKey(), e)
+	}
+
+	m := &material{cert: &cert, insecure: icfg.GetInsecure()}
+
+	if m.insecure {
+		return m, nil
+	}
+
+	cfg := &tls.Config{} //nolint:gosec
+	if e := loadCA(icfg.GetCa(), cfg); e != nil {
+		return nil, e
+	}
+
+	// request a client cert at least
+	m.pool, m.level = cfg.ClientCAs, icfg.GetLevel()
+	if m.level == NoClientCert {
+		m.level = RequestClientCert
+	}
+
+	return m, nil
+}
@@ -0,0 +1,150 @@
+package tls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCert generate a self signed certificate named cn in the dir directory,
+// returning the cert and key paths.
+func writeCert(t *testing.T, dir, cn string) (string, string) {
+	t.Helper()
+
+	key, e := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, e)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, e := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.Nil(t, e)
+
+	kder, e := x509.MarshalECPrivateKey(key)
+	require.Nil(t, e)
+
+	var (
+		cert = filepath.Join(dir, "tls.crt")
+		k    = filepath.Join(dir, "tls.key")
+	)
+
+	require.Nil(t, os.WriteFile(cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.Nil(t, os.WriteFile(k, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder}), 0o600))
+
+	return cert, k
+}
+
+func commonName(t *testing.T, c *tls.Certificate) string {
+	t.Helper()
+
+	leaf, e := x509.ParseCertificate(c.Certificate[0])
+	require.Nil(t, e)
+
+	return leaf.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	var (
+		dir       = t.TempDir()
+		cert, key = writeCert(t, dir, "first")
+		log       = slog.New(slog.NewTextHandler(io.Discard, nil))
+		icfg      = Config{Cert: cert, Key: key, Ca: cert, Level: RequireAndVerifyClientCert}
+	)
+
+	_, e := NewReloader(Config{Cert: "missing", Key: "missing"}, log)
+	require.NotNil(t, e)
+
+	r, e := NewReloader(icfg, log)
+	require.Nil(t, e)
+
+	cfg := r.Config()
+	assert.Empty(t, cfg.Certificates)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, cfg.ClientAuth)
+
+	served := func() *tls.Config {
+		c, e := cfg.GetConfigForClient(&tls.ClientHelloInfo{})
+		require.Nil(t, e)
+
+		return c
+	}
+
+	assert.Equal(t, "first", commonName(t, &served().Certificates[0]))
+
+	t.Log("reload swap the certificate and the client ca")
+	{
+		pool := served().ClientCAs
+
+		writeCert(t, dir, "second")
+		require.Nil(t, r.Reload())
+
+		c, e := cfg.GetCertificate(&tls.ClientHelloInfo{})
+		require.Nil(t, e)
+		assert.Equal(t, "second", commonName(t, c))
+		assert.Equal(t, "second", commonName(t, &served().Certificates[0]))
+		assert.False(t, pool.Equal(served().ClientCAs))
+	}
+
+	t.Log("invalid files keep the previous material")
+	{
+		require.Nil(t, os.WriteFile(key, []byte("garbage"), 0o600))
+		require.NotNil(t, r.Reload())
+		assert.Equal(t, "second", commonName(t, r.Certificate()))
+	}
+
+	t.Log("modified files are reloaded by the watcher")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			r.Watch(ctx, 10*time.Millisecond)
+			close(done)
+		}()
+
+		writeCert(t, dir, "third")
+
+		require.Eventually(t, func() bool {
+			return commonName(t, r.Certificate()) == "third"
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+		<-done
+	}
+
+	t.Log("swap load another config and its client auth")
+	{
+		other, okey := writeCert(t, t.TempDir(), "other")
+
+		require.NotNil(t, r.Swap(Config{Cert: "missing", Key: "missing"}))
+		assert.Equal(t, "third", commonName(t, r.Certificate()))
+
+		require.Nil(t, r.Swap(Config{Cert: other, Key: okey, Insecure: true}))
+		assert.Equal(t, "other", commonName(t, &served().Certificates[0]))
+		assert.Equal(t, tls.NoClientCert, served().ClientAuth)
+
+		writeCert(t, dir, "ignored")
+		require.Nil(t, r.Reload())
+		assert.Equal(t, "other", commonName(t, r.Certificate()))
+	}
+}
@@ -34,6 +34,12 @@ var (
 
 	// ErrParseUserCA error is returned in case of invalid ca cert path.
 	ErrParseUserCA = errors.New("failed to parse root certificate")
+
+	// ErrLoadCert error is returned in case of invalid cert or key file.
+	ErrLoadCert = errors.New("cannot load cert")
+
+	// ErrLoadCA error is returned in case of unreadable ca cert file.
+	ErrLoadCA = errors.New("cannot load ca cert")
 )
 
 // GetTLSCfg return a tls config ready for mTLS.
@@ -44,8 +50,8 @@ var (
 func GetTLSCfg(icfg IConfig, http2 ...bool) (*tls.Config, error) {
 	cert, err := tls.LoadX509KeyPair(icfg.GetCert(), icfg.GetKey())
 	if err != nil {
-		return nil, fmt.Errorf("cannot load cert [%s] and key [%s]: %w",
-			icfg.GetCert(), icfg.GetKey(), err)
+		return nil, fmt.Errorf("%w [%s] and key [%s]: %w",
+			ErrLoadCert, icfg.GetCert(), icfg.GetKey(), err)
 	}
 
 	/* #nosec */
@@ -80,7 +86,7 @@ func loadCA(caPath string, cfg *tls.Config) error {
 	pool := x509.NewCertPool()
 
 	if caCertPEM, e := os.ReadFile(caPath); e != nil {
-		return fmt.Errorf("cannot load ca cert %q in pool: %w", caPath, e)
+		return fmt.Errorf("%w %q in pool: %w", ErrLoadCA, caPath, e)
 	} else if !pool.AppendCertsFromPEM(caCertPEM) {
 		return ErrParseUserCA
 	}
@@ -90,9 +96,10 @@ func loadCA(caPath string, cfg *tls.Config) error {
 	return nil
 }
 
+// getBaseTLSCfg return the base tls config. The cert certificate is
+// served if not nil.
 func getBaseTLSCfg(cert *tls.Certificate, http2 ...bool) *tls.Config {
 	cfg := &tls.Config{
-		Certificates:             []tls.Certificate{*cert},
 		PreferServerCipherSuites: true,
 		CurvePreferences:         DefaultCurve,
 		MinVersion:               tls.VersionTLS12,
@@ -100,6 +107,10 @@ func getBaseTLSCfg(cert *tls.Certificate, http2 ...bool) *tls.Config {
 		CipherSuites:             DefaultCipher,
 	}
 
+	if cert != nil {
+		cfg.Certificates = []tls.Certificate{*cert}
+	}
+
 	if len(http2) > 0 && http2[0] {
 		cfg.NextProtos = append(cfg.NextProtos, H2TLSProto)
 	}
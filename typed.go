@@ -0,0 +1,212 @@
+package webfmwk
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+const _pathTag = "path"
+
+// TypedFunc hold the signature of a typed handler.
+type TypedFunc[Req, Resp any] func(c Context, req Req) (Resp, error)
+
+// Typed convert a TypedFunc to a HandlerFunc. The returned handler:
+//   - bind the request body (using the Content-Type codec), the query params
+//     (gorilla `schema` tags) and the url vars (`path` tags) into Req
+//   - validate Req (go-playground `validate` tags)
+//   - call fn and encode Resp using the Accept header codec, with the
+//     route Status code (200 by default, no payload for 204)
+//
+// Errors are returned to the handler chain, hence processed by HandleError.
+//
+//	type getUser struct {
+//		ID     int  `path:"id" validate:"min=1"`
+//		Pretty bool `schema:"pretty"`
+//	}
+//
+//	s.GET("/users/{id}", webfmwk.Typed(func(c webfmwk.Context, req getUser) (User, error) {
+//		return db.Get(req.ID)
+//	}))
+func Typed[Req, Resp any](fn TypedFunc[Req, Resp]) HandlerFunc {
+	var (
+		t        = reflect.TypeOf((*Req)(nil)).Elem()
+		isStruct = t.Kind() == reflect.Struct
+		hasQuery = isStruct && hasTag(t, "schema")
+		hasPath  = isStruct && hasTag(t, _pathTag)
+	)
+
+	return HandlerFunc(func(c Context) error {
+		var req Req
+
+		if len(c.GetFastContext().PostBody()) > 0 {
+			if e := c.FetchContent(&req); e != nil {
+				return e
+			}
+		}
+
+		if hasQuery {
+			if e := c.DecodeQP(&req); e != nil {
+				return e
+			}
+		}
+
+		if hasPath {
+			if e := bindPath(c, reflect.ValueOf(&req).Elem()); e != nil {
+				return e
+			}
+		}
+
+		if isStruct {
+			if e := c.Validate(req); e != nil {
+				return e
+			}
+		}
+
+		resp, e := fn(c, req)
+		if e != nil {
+			return e
+		}
+
+		status := http.StatusOK
+		if r := c.GetRoute(); r != nil {
+			status = routeStatus(*r)
+		}
+
+		if status == http.StatusNoContent {
+			c.SetStatusCode(status)
+
+			return nil
+		}
+
+		return c.Render(status, resp)
+	})
+}
+
+// TypedRoute return a Route exposing the fn TypedFunc. The Request, Query and
+// Response fields are filled for the OpenAPI document.
+func TypedRoute[Req, Resp any](verbe, path string, fn TypedFunc[Req, Resp]) Route {
+	var (
+		req   Req
+		resp  Resp
+		t     = reflect.TypeOf(&req).Elem()
+		route = Route{Verbe: verbe, Path: path, Handler: Typed(fn), Response: resp}
+	)
+
+	if t.Kind() == reflect.Struct {
+		switch verbe {
+		case POST, PUT, PATCH:
+			route.Request = req
+		}
+
+		if hasTag(t, "schema") {
+			route.Query = req
+		}
+	}
+
+	return route
+}
+
+// routeStatus return the route status code, 200 by default.
+func routeStatus(r Route) int {
+	if r.Status == 0 {
+		return http.StatusOK
+	}
+
+	return r.Status
+}
+
+// hasTag return true if at least one field of the t struct has the tag struct tag.
+func hasTag(t reflect.Type, tag string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bindPath load the url vars in the v struct fields holding a path tag.
+func bindPath(c Context, v reflect.Value) ErrorHandled {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := t.Field(i).Tag.Lookup(_pathTag)
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+
+		val := c.GetVar(name)
+		if val == "" {
+			continue
+		}
+
+		if e := setField(v.Field(i), val); e != nil {
+			return NewUnprocessable(NewErrorFromError(fmt.Errorf("path param %q: %w", name, e)))
+		}
+	}
+
+	return nil
+}
+
+// setField set the f field from the val string.
+func setField(f reflect.Value, val string) error {
+	if f.CanAddr() {
+		if tu, ok := f.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return tu.UnmarshalText([]byte(val))
+		}
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(val)
+
+	case reflect.Bool:
+		b, e := strconv.ParseBool(val)
+		if e != nil {
+			return e
+		}
+
+		f.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, e := strconv.ParseInt(val, 10, f.Type().Bits())
+		if e != nil {
+			return e
+		}
+
+		f.SetInt(i)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, e := strconv.ParseUint(val, 10, f.Type().Bits())
+		if e != nil {
+			return e
+		}
+
+		f.SetUint(u)
+
+	case reflect.Float32, reflect.Float64:
+		fl, e := strconv.ParseFloat(val, f.Type().Bits())
+		if e != nil {
+			return e
+		}
+
+		f.SetFloat(fl)
+
+	case reflect.Pointer:
+		p := reflect.New(f.Type().Elem())
+		if e := setField(p.Elem(), val); e != nil {
+			return e
+		}
+
+		f.Set(p)
+
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package webfmwk
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type (
+	typedReq struct {
+		Name    string `json:"name"   validate:"required"`
+		ID      int    `json:"-"      path:"id" validate:"min=1"`
+		Verbose bool   `json:"-"      schema:"verbose"`
+	}
+
+	typedResp struct {
+		Name    string `json:"name"`
+		ID      int    `json:"id"`
+		Verbose bool   `json:"verbose"`
+	}
+)
+
+func TestTyped(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	fn := func(c Context, req typedReq) (typedResp, error) {
+		if req.Name == "fail" {
+			return typedResp{}, NewConflict(NewError("already exist"))
+		} else if req.Name == "boom" {
+			return typedResp{}, errors.New("boom")
+		}
+
+		return typedResp{Name: req.Name, ID: req.ID, Verbose: req.Verbose}, nil
+	}
+
+	route := TypedRoute(POST, "/users/{id}", fn)
+	route.Status = http.StatusCreated
+
+	s.AddRoutes(route)
+	s.AddRoutes(Route{Verbe: DELETE, Path: "/users/{id}", Status: http.StatusNoContent,
+		Handler: Typed(func(c Context, req struct {
+			ID int `path:"id"`
+		},
+		) (struct{}, error) {
+			return struct{}{}, nil
+		})})
+
+	do := func(method, uri, body string) *fasthttp.RequestCtx {
+		fc := &fasthttp.RequestCtx{}
+		fc.Request.Header.SetMethod(method)
+		fc.Request.SetRequestURI(uri)
+
+		if body != "" {
+			fc.Request.Header.SetContentType(MIMEJSON)
+			fc.Request.SetBodyString(body)
+		}
+
+		s.GetRouter().Handler(fc)
+
+		return fc
+	}
+
+	tests := map[string]struct {
+		method, uri, body string
+		code              int
+		resp              string
+	}{
+		"bind all": {
+			method: POST, uri: "/users/42?verbose=true", body: `{"name":"tutu"}`,
+			code: http.StatusCreated, resp: `{"name":"tutu","id":42,"verbose":true}`,
+		},
+		"validation error": {
+			method: POST, uri: "/users/42", body: `{}`,
+			code: http.StatusUnprocessableEntity, resp: `{"message":{"name":"name is a required field"},"status":422}`,
+		},
+		"invalid path param": {
+			method: POST, uri: "/users/abc", body: `{"name":"tutu"}`,
+			code: http.StatusUnprocessableEntity,
+		},
+		"handled error": {
+			method: POST, uri: "/users/42", body: `{"name":"fail"}`,
+			code: http.StatusConflict, resp: `{"message":"already exist","status":409}`,
+		},
+		"internal error": {
+			method: POST, uri: "/users/42", body: `{"name":"boom"}`,
+			code: http.StatusInternalServerError, resp: `{"message":"boom","status":500}`,
+		},
+		"no content": {
+			method: DELETE, uri: "/users/42",
+			code: http.StatusNoContent,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fc := do(test.method, test.uri, test.body)
+			assert.Equal(t, test.code, fc.Response.StatusCode())
+
+			if test.resp != "" {
+				assert.Equal(t, test.resp, string(fc.Response.Body()))
+			}
+		})
+	}
+
+	t.Log("typed route are documented")
+	{
+		assert.Equal(t, typedReq{}, route.Request)
+		assert.Equal(t, typedReq{}, route.Query)
+		assert.Equal(t, typedResp{}, route.Response)
+	}
+}
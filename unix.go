@@ -0,0 +1,212 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	_staleSocketTimeout = time.Second
+
+	// _privateSocketDir prefix the private directories the unix sockets
+	// are bound in, see listenSocketFile.
+	_privateSocketDir = ".webfmwk-"
+)
+
+type (
+	// unixListener is an unix socket listener moved after its bind: the
+	// socket file is removed via its final path.
+	unixListener struct {
+		*net.UnixListener
+		path   string
+		unlink atomic.Bool
+	}
+
+	// PeerCredentials hold the credentials of the process connected via
+	// an unix socket, see Context.GetPeerCredentials.
+	PeerCredentials struct {
+		// PID hold the peer process id.
+		PID int32 `json:"pid"`
+
+		// UID hold the peer process user id.
+		UID uint32 `json:"uid"`
+
+		// GID hold the peer process group id.
+		GID uint32 `json:"gid"`
+	}
+)
+
+// Username return the name of the peer process user.
+func (pc PeerCredentials) Username() (string, error) {
+	u, e := user.LookupId(strconv.FormatUint(uint64(pc.UID), 10))
+	if e != nil {
+		return "", e
+	}
+
+	return u.Username, nil
+}
+
+// removeStaleSocket remove the file unix socket if no process serve it.
+// A live socket or a file which isn't a socket are left untouched.
+func removeStaleSocket(file string) error {
+	fi, e := os.Lstat(file)
+
+	switch {
+	case errors.Is(e, os.ErrNotExist):
+		return nil
+	case e != nil:
+		return e
+	case fi.Mode()&os.ModeSocket == 0:
+		return fmt.Errorf("%w: %q isn't an unix socket", ErrAddressInUse, file)
+	}
+
+	c, e := net.DialTimeout("unix", file, _staleSocketTimeout)
+	if e == nil {
+		c.Close()
+
+		return fmt.Errorf("%w: %q is served by another process", ErrAddressInUse, file)
+	}
+
+	if errors.Is(e, os.ErrPermission) {
+		return e
+	}
+
+	if e := os.Remove(file); e != nil && !errors.Is(e, os.ErrNotExist) {
+		return fmt.Errorf("removing stale unix socket %q: %w", file, e)
+	}
+
+	return nil
+}
+
+// Close implement net.Listener, removing the socket file unless
+// SetUnlinkOnClose(false) was called.
+func (ul *unixListener) Close() error {
+	e := ul.UnixListener.Close()
+
+	if ul.unlink.Load() {
+		_ = os.Remove(ul.path)
+	}
+
+	return e
+}
+
+// SetUnlinkOnClose set whether the socket file is removed by Close.
+func (ul *unixListener) SetUnlinkOnClose(unlink bool) { ul.unlink.Store(unlink) }
+
+// listenSocketFile bind the ep unix socket in a private directory, apply its
+// owner, group and mode, and move it to its path: no client may connect
+// before the permissions are applied.
+func listenSocketFile(ctx context.Context, ep Endpoint) (net.Listener, error) {
+	dir, e := os.MkdirTemp(filepath.Dir(ep.Addr), _privateSocketDir)
+	if e != nil {
+		return nil, fmt.Errorf("creating the unix socket directory: %w", e)
+	}
+
+	defer os.RemoveAll(dir)
+
+	tmp := ep
+	tmp.Addr = filepath.Join(dir, "s")
+
+	nl, e := tmp.Listen(ctx)
+	if e != nil {
+		return nil, e
+	}
+
+	ul, ok := nl.(*net.UnixListener)
+	if !ok {
+		nl.Close()
+
+		return nil, fmt.Errorf("unexpected unix socket listener %T", nl)
+	}
+
+	ul.SetUnlinkOnClose(false)
+
+	if e := setSocketFile(tmp.Addr, ep); e != nil {
+		ul.Close()
+
+		return nil, e
+	}
+
+	if e := os.Rename(tmp.Addr, ep.Addr); e != nil {
+		ul.Close()
+
+		return nil, fmt.Errorf("moving unix socket %q: %w", ep.Addr, e)
+	}
+
+	ret := &unixListener{UnixListener: ul, path: ep.Addr}
+	ret.unlink.Store(true)
+
+	return ret, nil
+}
+
+// setSocketFile apply the ep endpoint owner, group and mode to the file
+// unix socket file.
+func setSocketFile(file string, ep Endpoint) error {
+	if ep.Owner != "" || ep.Group != "" {
+		uid, gid, e := lookupOwner(ep.Owner, ep.Group)
+		if e != nil {
+			return e
+		}
+
+		if e := os.Lchown(file, uid, gid); e != nil {
+			return fmt.Errorf("chown unix socket %q: %w", ep.Addr, e)
+		}
+	}
+
+	if ep.Mode != 0 {
+		if e := os.Chmod(file, ep.Mode); e != nil {
+			return fmt.Errorf("chmod unix socket %q: %w", ep.Addr, e)
+		}
+	}
+
+	return nil
+}
+
+// hasSocketFile return true if the ep endpoint set the unix socket file
+// owner, group or mode.
+func (ep Endpoint) hasSocketFile() bool {
+	return ep.Mode != 0 || ep.Owner != "" || ep.Group != ""
+}
+
+// lookupOwner resolve the owner and group names or ids, -1 if unset.
+func lookupOwner(owner, group string) (int, int, error) {
+	uid, gid := -1, -1
+
+	if owner != "" {
+		id, e := strconv.Atoi(owner)
+		if e != nil {
+			u, e := user.Lookup(owner)
+			if e != nil {
+				return uid, gid, fmt.Errorf("%w: %w", ErrInvalidAddress, e)
+			}
+
+			id, _ = strconv.Atoi(u.Uid)
+		}
+
+		uid = id
+	}
+
+	if group != "" {
+		id, e := strconv.Atoi(group)
+		if e != nil {
+			g, e := user.LookupGroup(group)
+			if e != nil {
+				return uid, gid, fmt.Errorf("%w: %w", ErrInvalidAddress, e)
+			}
+
+			id, _ = strconv.Atoi(g.Gid)
+		}
+
+		gid = id
+	}
+
+	return uid, gid, nil
+}
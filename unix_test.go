@@ -0,0 +1,153 @@
+package webfmwk
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func unixClient(sock string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		},
+	}}
+}
+
+func TestUnixSocket(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		sock = filepath.Join(dir, "app.sock")
+		addr = Address{Addr: "unix://" + sock + "?mode=0600&owner=" +
+			strconv.Itoa(os.Getuid()) + "&group=" + strconv.Itoa(os.Getgid())}
+	)
+
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	s.GET("/whoami", func(c Context) error {
+		cred, ok := c.GetPeerCredentials()
+		if !ok {
+			return c.JSONNoContent()
+		}
+
+		return c.JSONOk(cred)
+	})
+
+	t.Log("a stale socket is removed")
+	{
+		nl, e := net.Listen("unix", sock)
+		require.Nil(t, e)
+		nl.(*net.UnixListener).SetUnlinkOnClose(false)
+		require.Nil(t, nl.Close())
+		require.FileExists(t, sock)
+	}
+
+	require.Nil(t, s.Reconfigure(Addresses{addr}).Err())
+
+	fi, e := os.Stat(sock)
+	require.Nil(t, e)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		assert.Equal(t, uint32(os.Getuid()), st.Uid)
+		assert.Equal(t, uint32(os.Getgid()), st.Gid)
+	}
+
+	t.Log("the private bind directory is removed")
+	{
+		entries, e := os.ReadDir(dir)
+		require.Nil(t, e)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "app.sock", entries[0].Name())
+	}
+
+	t.Log("the handlers see the peer credentials")
+	{
+		resp, e := unixClient(sock).Get("http://unix/whoami") //nolint:noctx
+		require.Nil(t, e)
+
+		defer resp.Body.Close()
+
+		if runtime.GOOS == "linux" {
+			var cred PeerCredentials
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Nil(t, json.NewDecoder(resp.Body).Decode(&cred))
+			assert.Equal(t, PeerCredentials{
+				PID: int32(os.Getpid()), UID: uint32(os.Getuid()), GID: uint32(os.Getgid()),
+			}, cred)
+
+			name, e := cred.Username()
+			if e == nil {
+				assert.NotEmpty(t, name)
+			}
+		} else {
+			assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		}
+	}
+
+	t.Log("a live socket isn't stolen")
+	{
+		other, e := InitServer()
+		require.Nil(t, e)
+
+		e = other.Serve(context.Background(), addr)
+		assert.ErrorIs(t, e, ErrAddressInUse)
+		require.FileExists(t, sock)
+	}
+
+	t.Log("the socket file is removed on shutdown")
+	{
+		require.Nil(t, s.ShutdownAndWait())
+		assert.NoFileExists(t, sock)
+	}
+
+	t.Log("a regular file isn't removed")
+	{
+		file := filepath.Join(dir, "regular")
+		require.Nil(t, os.WriteFile(file, []byte("data"), 0o600))
+
+		s, e := InitServer()
+		require.Nil(t, e)
+
+		assert.ErrorIs(t, s.Serve(context.Background(), Address{Addr: "unix://" + file}), ErrAddressInUse)
+		assert.FileExists(t, file)
+	}
+
+	t.Log("the owner alone keep the umask mode")
+	{
+		old := syscall.Umask(0o027)
+		defer syscall.Umask(old)
+
+		s, e := InitServer()
+		require.Nil(t, e)
+
+		require.Nil(t, s.Reconfigure(Addresses{{Addr: "unix://" + sock + "?owner=" + strconv.Itoa(os.Getuid())}}).Err())
+
+		fi, e := os.Stat(sock)
+		require.Nil(t, e)
+		assert.Equal(t, os.FileMode(0o750), fi.Mode().Perm())
+		require.Nil(t, s.ShutdownAndWait())
+	}
+
+	t.Log("unknown owner")
+	{
+		s, e := InitServer()
+		require.Nil(t, e)
+
+		e = s.Serve(context.Background(), Address{Addr: "unix://" + sock + "?owner=no-such-user-webfmwk"})
+		assert.ErrorIs(t, e, ErrInvalidAddress)
+		assert.NoFileExists(t, sock)
+	}
+}
@@ -0,0 +1,316 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// _envUpgradeAddrs hold the json list of the addresses of the listeners
+	// passed by the upgraded process, in the fd order.
+	_envUpgradeAddrs = "WEBFMWK_UPGRADE_ADDRS"
+
+	// _envUpgradeReady hold the fd used to notify the upgraded process.
+	_envUpgradeReady = "WEBFMWK_UPGRADE_READY_FD"
+
+	// _upgradeFDsStart is the first file descriptor passed to the new process.
+	_upgradeFDsStart = 3
+
+	// _upgradeTimeout bound the wait of the new process on signal triggered upgrade.
+	_upgradeTimeout = 30 * time.Second
+)
+
+var (
+	// ErrUpgradeInProgress is returned if an upgrade is already running.
+	ErrUpgradeInProgress = errors.New("upgrade already in progress")
+
+	// ErrUpgradeFailed is returned if the new process exited or timed out
+	// before being ready.
+	ErrUpgradeFailed = errors.New("upgrade failed")
+)
+
+type (
+	// filer is implemented by the net.Listener able to expose their fd.
+	filer interface {
+		File() (*os.File, error)
+	}
+
+	// upgrade hold the listeners passed by the upgraded process.
+	upgrade struct {
+		fds   map[string]net.Listener
+		ready *os.File
+		once  sync.Once
+	}
+)
+
+// WithUpgradeSignal trigger a zero downtime Upgrade when the sig signal
+// (usually syscall.SIGUSR2) is received.
+func WithUpgradeSignal(sig os.Signal) Option {
+	return func(s *Server) {
+		s.meta.upgradeSignal = sig
+		s.slog.Debug("\t-- upgrade signal loaded", slog.String("signal", sig.String()))
+	}
+}
+
+// Upgrade re-execute the running binary, passing it the listening sockets.
+// The new process serve the same set of Address on the inherited sockets
+// (TCP, TLS and unix socket) and, once all are served, notify the current
+// process which then gracefully shutdown, so Serve or Run return.
+//
+// If the new process exit or the ctx context expire before the notification,
+// the current process keep serving and an ErrUpgradeFailed error is returned.
+func (s *Server) Upgrade(ctx context.Context) error {
+	exe, e := os.Executable()
+	if e != nil {
+		return fmt.Errorf("%w: locating the executable: %w", ErrUpgradeFailed, e)
+	}
+
+	cmd := exec.Command(exe, os.Args[1:]...) //nolint:gosec
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.Env = os.Environ()
+
+	return s.upgrade(ctx, cmd)
+}
+
+// upgrade start the cmd process with the listening sockets and wait for it
+// to be ready before shutting down.
+func (s *Server) upgrade(ctx context.Context, cmd *exec.Cmd) error {
+	if !s.reconfMu.TryLock() {
+		return ErrUpgradeInProgress
+	}
+	defer s.reconfMu.Unlock()
+
+	var (
+		lns   = s.listeners.snapshot()
+		addrs = make([]string, 0, len(lns))
+		files = make([]*os.File, 0, len(lns)+1)
+	)
+
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	for _, ln := range lns {
+		fl, ok := ln.raw.(filer)
+		if !ok {
+			return fmt.Errorf("%w: listener %q cannot be passed", ErrUpgradeFailed, ln.Addr)
+		}
+
+		f, e := fl.File()
+		if e != nil {
+			return fmt.Errorf("%w: listener %q: %w", ErrUpgradeFailed, ln.Addr, e)
+		}
+
+		files, addrs = append(files, f), append(addrs, ln.Addr)
+	}
+
+	r, w, e := os.Pipe()
+	if e != nil {
+		return fmt.Errorf("%w: %w", ErrUpgradeFailed, e)
+	}
+
+	defer r.Close()
+
+	files = append(files, w)
+
+	raw, _ := json.Marshal(addrs)
+	cmd.ExtraFiles = files
+	cmd.Env = append(cmd.Env,
+		_envUpgradeAddrs+"="+string(raw),
+		_envUpgradeReady+"="+strconv.Itoa(_upgradeFDsStart+len(addrs)))
+
+	// the socket files must survive the current listeners
+	unlinkOnClose(lns, false)
+
+	s.slog.Info("upgrade: starting the new process", slog.Int("listeners", len(addrs)))
+
+	e = cmd.Start()
+
+	for _, ln := range lns {
+		if e := setNonblock(ln.raw); e != nil {
+			s.slog.Error("upgrade: restoring the listener", slog.String("address", ln.Addr), slog.Any("error", e))
+		}
+	}
+
+	if e != nil {
+		unlinkOnClose(lns, true)
+
+		return fmt.Errorf("%w: %w", ErrUpgradeFailed, e)
+	}
+
+	w.Close()
+
+	ready := make(chan error, 1)
+
+	go func() {
+		// an EOF mean the new process exited without notifying
+		_, e := r.Read(make([]byte, 1))
+		ready <- e
+	}()
+
+	select {
+	case e = <-ready:
+	case <-ctx.Done():
+		e = ctx.Err()
+	}
+
+	if e != nil {
+		if errors.Is(e, io.EOF) {
+			e = errors.New("new process exited")
+		}
+
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+
+		unlinkOnClose(lns, true)
+
+		return fmt.Errorf("%w: %w", ErrUpgradeFailed, e)
+	}
+
+	s.slog.Info("upgrade: new process ready, shutting down", slog.Int("pid", cmd.Process.Pid))
+
+	if e := s.Shutdown(); e != nil {
+		s.slog.Error("upgrade: shutdown", slog.Any("error", e))
+	}
+
+	return nil
+}
+
+// unlinkOnClose set whether the unix socket files are removed when the
+// lns listeners are closed.
+func unlinkOnClose(lns []*listener, unlink bool) {
+	for _, ln := range lns {
+		if ul, ok := ln.raw.(interface{ SetUnlinkOnClose(bool) }); ok {
+			ul.SetUnlinkOnClose(unlink)
+		}
+	}
+}
+
+// upgradeListener return the listener of addr passed by the upgraded
+// process, nil if none.
+func (s *Server) upgradeListener(addr string) (net.Listener, error) {
+	in := &s.inherited
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if !in.upgradeLoaded {
+		in.upgradeLoaded = true
+		in.upgrade.fds, in.upgrade.ready, in.upgradeErr = upgradeFDs(
+			_upgradeFDsStart, os.Getenv(_envUpgradeAddrs), os.Getenv(_envUpgradeReady))
+
+		_ = os.Unsetenv(_envUpgradeAddrs)
+		_ = os.Unsetenv(_envUpgradeReady)
+	}
+
+	if in.upgradeErr != nil {
+		return nil, in.upgradeErr
+	}
+
+	nl := in.upgrade.fds[addr]
+	delete(in.upgrade.fds, addr)
+
+	return nl, nil
+}
+
+// upgradeFDs turn the file descriptors starting at start into listeners
+// keyed by the addrs json list, and return the ready notification file.
+func upgradeFDs(start int, addrs, ready string) (map[string]net.Listener, *os.File, error) {
+	if addrs == "" {
+		return nil, nil, nil
+	}
+
+	var list []string
+	if e := json.Unmarshal([]byte(addrs), &list); e != nil {
+		return nil, nil, fmt.Errorf("invalid %s value: %w", _envUpgradeAddrs, e)
+	}
+
+	ret := make(map[string]net.Listener, len(list))
+
+	for i := range list {
+		f := os.NewFile(uintptr(start+i), list[i])
+		nl, e := net.FileListener(f)
+		f.Close()
+
+		if e != nil {
+			return nil, nil, fmt.Errorf("upgrade socket %q (fd %d): %w", list[i], start+i, e)
+		}
+
+		ret[list[i]] = nl
+	}
+
+	fd, e := strconv.Atoi(ready)
+	if e != nil {
+		return nil, nil, fmt.Errorf("invalid %s value %q", _envUpgradeReady, ready)
+	}
+
+	return ret, os.NewFile(uintptr(fd), "upgrade"), nil
+}
+
+// upgradeReady notify the upgraded process, if any, that all the addresses
+// are served, and close the unused inherited listeners.
+func (s *Server) upgradeReady() {
+	in := &s.inherited
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.upgrade.once.Do(func() {
+		for addr, nl := range in.upgrade.fds {
+			s.slog.Warn("upgrade: closing the unused inherited socket", slog.String("address", addr))
+			nl.Close()
+		}
+
+		in.upgrade.fds = nil
+
+		if in.upgrade.ready == nil {
+			return
+		}
+
+		s.slog.Info("upgrade: notifying the previous process")
+
+		if _, e := in.upgrade.ready.Write([]byte{1}); e != nil {
+			s.slog.Error("upgrade: notifying the previous process", slog.Any("error", e))
+		}
+
+		in.upgrade.ready.Close()
+	})
+}
+
+// upgradeHandler trigger an Upgrade on the configured signal.
+func (s *Server) upgradeHandler() {
+	c := make(chan os.Signal, 1)
+
+	signal.Notify(c, s.meta.upgradeSignal)
+	defer signal.Stop(c)
+
+	for {
+		select {
+		case <-c:
+			s.slog.Info("captured upgrade signal")
+
+			ctx, cancel := context.WithTimeout(s.ctx, _upgradeTimeout)
+			if e := s.Upgrade(ctx); e != nil {
+				s.slog.Error("upgrade", slog.Any("error", e))
+			}
+
+			cancel()
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package webfmwk
+
+import (
+	"context"
+	fmtls "crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/burgesQ/webfmwk/v6/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// _envUpgradeChild hold the addresses served by TestUpgradeChild.
+const _envUpgradeChild = "WEBFMWK_TEST_UPGRADE_CHILD"
+
+func pidServer(t *testing.T) *Server {
+	t.Helper()
+
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	s.GET("/pid", func(c Context) error { return c.JSONOk(os.Getpid()) })
+	s.GET("/quit", func(c Context) error {
+		go func() { _ = s.Shutdown() }()
+
+		return c.JSONOk(os.Getpid())
+	})
+
+	return s
+}
+
+// TestUpgradeChild is the new process started by TestUpgrade.
+func TestUpgradeChild(t *testing.T) {
+	raw := os.Getenv(_envUpgradeChild)
+	if raw == "" {
+		t.Skip("started by TestUpgrade")
+	}
+
+	var addrs Addresses
+
+	require.Nil(t, json.Unmarshal([]byte(raw), &addrs))
+	require.Nil(t, pidServer(t).Serve(context.Background(), addrs...))
+}
+
+func TestUpgrade(t *testing.T) {
+	var (
+		s     = pidServer(t)
+		sock  = filepath.Join(t.TempDir(), "upgrade.sock")
+		addrs = Addresses{
+			{Addr: freeAddr(t), Name: "http"},
+			{Addr: freeAddr(t), Name: "https", TLS: &tls.Config{
+				Cert: "./example/ssl.crt", Key: "./example/ssl.key", Insecure: true,
+			}},
+			{Addr: _unixSocketPrefix + sock, Name: "unix"},
+		}
+		done = make(chan error)
+	)
+
+	go func() { done <- s.Serve(context.Background(), addrs...) }()
+
+	require.Eventually(t, func() bool { return len(s.Listeners()) == len(addrs) },
+		time.Second, 10*time.Millisecond)
+
+	var (
+		client = &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &fmtls.Config{InsecureSkipVerify: true}, //nolint:gosec
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				if strings.HasPrefix(addr, "unix") {
+					return (&net.Dialer{}).DialContext(ctx, "unix", sock)
+				}
+
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		}}
+		pids = func(path string) []int {
+			ret := make([]int, 0, len(addrs))
+
+			for _, uri := range []string{
+				"http://" + addrs[0].Addr, "https://" + addrs[1].Addr, "http://unix",
+			} {
+				resp, e := client.Get(uri + path) //nolint:noctx
+				require.Nil(t, e, uri)
+
+				body, e := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				require.Nil(t, e)
+
+				pid, e := strconv.Atoi(string(body))
+				require.Nil(t, e, string(body))
+
+				ret = append(ret, pid)
+			}
+
+			return ret
+		}
+		child = func(env string) *exec.Cmd {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestUpgradeChild$", "-test.count=1") //nolint:gosec
+			cmd.Env = append(os.Environ(), _envUpgradeChild+"="+env)
+
+			return cmd
+		}
+		me = os.Getpid()
+	)
+
+	assert.Equal(t, []int{me, me, me}, pids("/pid"))
+
+	t.Log("a failing new process keep the current one serving")
+	{
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		assert.ErrorIs(t, s.upgrade(ctx, child("garbage")), ErrUpgradeFailed)
+		assert.Equal(t, []int{me, me, me}, pids("/pid"))
+	}
+
+	t.Log("the new process take over the listeners")
+	{
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		raw, e := json.Marshal(addrs)
+		require.Nil(t, e)
+
+		cmd := child(string(raw))
+		require.Nil(t, s.upgrade(ctx, cmd))
+		require.Nil(t, <-done)
+
+		pid := cmd.Process.Pid
+		assert.Equal(t, []int{pid, pid, pid}, pids("/pid"))
+
+		resp, e := client.Get("http://unix/quit") //nolint:noctx
+		require.Nil(t, e)
+		resp.Body.Close()
+
+		require.Nil(t, cmd.Wait())
+	}
+}
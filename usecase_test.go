@@ -205,7 +205,7 @@ func TestUseCase(t *testing.T) {
 		"push_wrong_header": {
 			action: _pushNTest, url: "/api/world", pushContent: []byte(`{"first_name":"jean", "last_name":"claude"}`),
 			headers: [][2]string{{"Content-Type", "plain-text"}},
-			body:    `{"message":"Content-Type is not application/json","status":406}`, code: http.StatusNotAcceptable,
+			body:    `{"message":"Content-Type is not supported","status":406}`, code: http.StatusNotAcceptable,
 		},
 
 		"push_form_miss_field": {
@@ -0,0 +1,328 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// WorkerCriticalPolicy stop the server once the worker return.
+	WorkerCriticalPolicy WorkerPolicy = "critical"
+	// WorkerRestartPolicy restart the worker, with an exponential backoff.
+	WorkerRestartPolicy WorkerPolicy = "restart"
+	// WorkerOneShotPolicy run the worker once, its end being only reported.
+	WorkerOneShotPolicy WorkerPolicy = "one-shot"
+
+	// WorkerRunning is the state of a running worker.
+	WorkerRunning WorkerState = "running"
+	// WorkerRestarting is the state of a worker waiting for its restart.
+	WorkerRestarting WorkerState = "restarting"
+	// WorkerDone is the state of a worker which returned without error.
+	WorkerDone WorkerState = "done"
+	// WorkerFailed is the state of a worker which returned an error.
+	WorkerFailed WorkerState = "failed"
+	// WorkerStopped is the state of a worker ended by the server shutdown.
+	WorkerStopped WorkerState = "stopped"
+
+	_defaultWorkerBackoff    = 100 * time.Millisecond
+	_defaultWorkerMaxBackoff = 30 * time.Second
+)
+
+// ErrWorkerExists is returned by StartWorker if a worker of the same name
+// is still running.
+var ErrWorkerExists = errors.New("worker already running")
+
+type (
+	// WorkerFunc is a supervised worker job. The ctx context is canceled when
+	// the server stop, the job is expected to return then.
+	WorkerFunc func(ctx context.Context) error
+
+	// WorkerPolicy define what's done once a worker return.
+	WorkerPolicy string
+
+	// WorkerState hold the state of a supervised worker.
+	WorkerState string
+
+	// WorkerOption configure a worker started via StartWorker.
+	WorkerOption func(w *worker)
+
+	// WorkerStatus hold the status of a supervised worker.
+	WorkerStatus struct {
+		// Started hold the time of the last (re)start.
+		Started time.Time `json:"started"`
+
+		// Stopped hold the time of the last end, zero if the worker never ended.
+		Stopped time.Time `json:"stopped,omitempty"`
+
+		// Name identify the worker.
+		Name string `json:"name"`
+
+		// Policy hold the worker policy.
+		Policy WorkerPolicy `json:"policy"`
+
+		// State hold the worker state.
+		State WorkerState `json:"state"`
+
+		// Error hold the last error returned by the worker, if any.
+		Error string `json:"error,omitempty"`
+
+		// Restarts hold the number of restarts.
+		Restarts int `json:"restarts"`
+	}
+
+	// WorkerError is the server failure reported by Serve when a critical
+	// worker return, or when a restarted worker exhaust its restarts.
+	WorkerError struct {
+		// Err hold the error returned by the worker, nil if it returned
+		// without error.
+		Err error
+
+		// Name hold the worker name.
+		Name string
+	}
+
+	// worker hold a supervised worker.
+	worker struct {
+		fn          WorkerFunc
+		status      WorkerStatus
+		backoff     time.Duration
+		maxBackoff  time.Duration
+		maxRestarts int
+	}
+
+	// workers hold the supervised workers of a Server.
+	workers struct {
+		all map[string]*worker
+		mu  sync.RWMutex
+	}
+)
+
+// Error implement the error interface.
+func (e *WorkerError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("worker %q: returned", e.Name)
+	}
+
+	return fmt.Sprintf("worker %q: %v", e.Name, e.Err)
+}
+
+// Unwrap allow errors.Is and errors.As to reach the underlying error.
+func (e *WorkerError) Unwrap() error { return e.Err }
+
+// WorkerCritical stop the server once the worker return, Serve then
+// returning a *WorkerError. It's the default policy.
+func WorkerCritical() WorkerOption {
+	return func(w *worker) { w.status.Policy = WorkerCriticalPolicy }
+}
+
+// WorkerRestart restart the worker once it return, after a backoff starting
+// at backoff and doubling up to maxBackoff. The backoff is reset if the
+// worker ran for more than maxBackoff. Once maxRestarts restarts are
+// reached, the worker is handled as a critical one. A zero maxRestarts
+// allow unlimited restarts, zero backoffs use the defaults (100ms and 30s).
+func WorkerRestart(backoff, maxBackoff time.Duration, maxRestarts int) WorkerOption {
+	return func(w *worker) {
+		w.status.Policy = WorkerRestartPolicy
+		w.backoff, w.maxBackoff, w.maxRestarts = backoff, maxBackoff, maxRestarts
+	}
+}
+
+// WorkerOneShot run the worker once, its end being only logged and reported
+// by its status.
+func WorkerOneShot() WorkerOption {
+	return func(w *worker) { w.status.Policy = WorkerOneShotPolicy }
+}
+
+// StartWorker launch the fn named worker, supervised according to its policy
+// (see WorkerCritical, WorkerRestart and WorkerOneShot). The server wait for
+// the workers to return before stopping, see WaitForStop. A panic in fn is
+// recovered and handled as an error.
+//
+// ErrWorkerExists is returned if a worker named name is still running, and
+// ErrServerStopped if the server is stopped.
+func (s *Server) StartWorker(name string, fn WorkerFunc, opts ...WorkerOption) error {
+	w := &worker{
+		fn:     fn,
+		status: WorkerStatus{Name: name, Policy: WorkerCriticalPolicy, State: WorkerRunning},
+	}
+
+	for _, o := range opts {
+		o(w)
+	}
+
+	if w.backoff <= 0 {
+		w.backoff = _defaultWorkerBackoff
+	}
+
+	if w.maxBackoff < w.backoff {
+		w.maxBackoff = max(_defaultWorkerMaxBackoff, w.backoff)
+	}
+
+	if s.ctx.Err() != nil {
+		return ErrServerStopped
+	}
+
+	s.workers.mu.Lock()
+
+	if prev, ok := s.workers.all[name]; ok && !prev.status.State.ended() {
+		s.workers.mu.Unlock()
+
+		return fmt.Errorf("%w: %q", ErrWorkerExists, name)
+	}
+
+	w.status.Started = time.Now()
+	s.workers.all[name] = w
+	s.wg.Add(1)
+	s.workers.mu.Unlock()
+
+	s.Logger(LogWorkers).Debug("worker: starting",
+		slog.String("name", name), slog.String("policy", string(w.status.Policy)))
+
+	go s.supervise(w)
+
+	return nil
+}
+
+// Workers return the status of the supervised workers, sorted by name.
+func (s *Server) Workers() []WorkerStatus {
+	s.workers.mu.RLock()
+	defer s.workers.mu.RUnlock()
+
+	ret := make([]WorkerStatus, 0, len(s.workers.all))
+	for _, w := range s.workers.all {
+		ret = append(ret, w.status)
+	}
+
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+
+	return ret
+}
+
+// Worker return the status of the name worker.
+func (s *Server) Worker(name string) (WorkerStatus, bool) {
+	s.workers.mu.RLock()
+	defer s.workers.mu.RUnlock()
+
+	w, ok := s.workers.all[name]
+	if !ok {
+		return WorkerStatus{}, false
+	}
+
+	return w.status, true
+}
+
+// supervise run the w worker until it end according to its policy.
+func (s *Server) supervise(w *worker) {
+	defer s.wg.Done()
+
+	var (
+		name    = w.status.Name
+		backoff = w.backoff
+		lg      = s.Logger(LogWorkers)
+	)
+
+	for {
+		start := time.Now()
+		e := runWorker(s.ctx, w.fn)
+
+		if s.ctx.Err() != nil {
+			lg.Info("worker: done", slog.String("name", name), slog.Any("error", e))
+			s.setWorker(w, WorkerStopped, e)
+
+			return
+		}
+
+		if e != nil {
+			lg.Error("worker: failure", slog.String("name", name), slog.Any("error", e))
+		}
+
+		switch w.status.Policy {
+		case WorkerOneShotPolicy:
+			s.setWorker(w, Tern(e == nil,
+				func() WorkerState { return WorkerDone }, func() WorkerState { return WorkerFailed }), e)
+
+			return
+
+		case WorkerRestartPolicy:
+			if time.Since(start) > w.maxBackoff {
+				backoff = w.backoff
+			}
+
+			if w.maxRestarts == 0 || w.status.Restarts < w.maxRestarts {
+				s.setWorker(w, WorkerRestarting, e)
+				lg.Warn("worker: restarting", slog.String("name", name), slog.Duration("backoff", backoff))
+
+				if !s.restartWorker(w, backoff) {
+					s.setWorker(w, WorkerStopped, e)
+
+					return
+				}
+
+				backoff = min(2*backoff, w.maxBackoff)
+
+				continue
+			}
+
+			lg.Error("worker: too many restarts", slog.String("name", name), slog.Int("restarts", w.status.Restarts))
+		}
+
+		// critical worker, or restarts exhausted
+		s.setWorker(w, WorkerFailed, e)
+		s.setFailure(&WorkerError{Name: name, Err: e})
+		s.cancel()
+
+		return
+	}
+}
+
+// restartWorker wait for the backoff before marking the w worker as running,
+// false being returned if the server stopped first.
+func (s *Server) restartWorker(w *worker, backoff time.Duration) bool {
+	t := time.NewTimer(backoff)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-s.ctx.Done():
+		return false
+	}
+
+	s.workers.mu.Lock()
+	w.status.State, w.status.Started = WorkerRunning, time.Now()
+	w.status.Restarts++
+	s.workers.mu.Unlock()
+
+	return true
+}
+
+// setWorker update the w worker state and last error.
+func (s *Server) setWorker(w *worker, state WorkerState, e error) {
+	s.workers.mu.Lock()
+	defer s.workers.mu.Unlock()
+
+	w.status.State, w.status.Stopped = state, time.Now()
+	if e != nil {
+		w.status.Error = e.Error()
+	}
+}
+
+// runWorker run the fn worker, turning a panic into an error.
+func runWorker(ctx context.Context, fn WorkerFunc) (e error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// ended return true if the worker won't run anymore.
+func (ws WorkerState) ended() bool {
+	return ws == WorkerDone || ws == WorkerFailed || ws == WorkerStopped
+}
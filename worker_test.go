@@ -0,0 +1,123 @@
+package webfmwk
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitWorker(t *testing.T, s *Server, name string, state WorkerState) WorkerStatus {
+	t.Helper()
+
+	var ws WorkerStatus
+
+	require.Eventually(t, func() bool {
+		ws, _ = s.Worker(name)
+
+		return ws.State == state
+	}, 5*time.Second, 5*time.Millisecond, "worker %q state %q", name, ws.State)
+
+	return ws
+}
+
+func TestWorkerPolicies(t *testing.T) {
+	s, e := InitServer()
+	require.Nil(t, e)
+
+	var (
+		runs atomic.Int32
+		fail = errors.New("boom")
+	)
+
+	require.Nil(t, s.StartWorker("loop", func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	}))
+
+	require.ErrorIs(t, s.StartWorker("loop", func(context.Context) error { return nil }), ErrWorkerExists)
+
+	t.Run("one shot", func(t *testing.T) {
+		require.Nil(t, s.StartWorker("once", func(context.Context) error { return fail }, WorkerOneShot()))
+
+		ws := waitWorker(t, s, "once", WorkerFailed)
+		assert.Equal(t, "boom", ws.Error)
+		assert.Equal(t, WorkerOneShotPolicy, ws.Policy)
+
+		// ended workers may be started again
+		require.Nil(t, s.StartWorker("once", func(context.Context) error { return nil }, WorkerOneShot()))
+		waitWorker(t, s, "once", WorkerDone)
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		require.Nil(t, s.StartWorker("flaky", func(ctx context.Context) error {
+			if runs.Add(1) < 3 {
+				panic("flaky")
+			}
+
+			<-ctx.Done()
+
+			return nil
+		}, WorkerRestart(time.Millisecond, 5*time.Millisecond, 5)))
+
+		ws := waitWorker(t, s, "flaky", WorkerRunning)
+		require.Eventually(t, func() bool { return runs.Load() == 3 }, 5*time.Second, 5*time.Millisecond)
+
+		ws, _ = s.Worker("flaky")
+		assert.Equal(t, 2, ws.Restarts)
+		assert.Equal(t, "panic: flaky", ws.Error)
+	})
+
+	assert.Nil(t, s.getFailure())
+
+	names := make([]string, 0, 3)
+	for _, ws := range s.Workers() {
+		names = append(names, ws.Name)
+	}
+
+	assert.Equal(t, []string{"flaky", "loop", "once"}, names)
+
+	require.Nil(t, s.Shutdown())
+	s.WaitForStop()
+
+	assert.Equal(t, WorkerStopped, waitWorker(t, s, "loop", WorkerStopped).State)
+	assert.ErrorIs(t, s.StartWorker("late", func(context.Context) error { return nil }), ErrServerStopped)
+}
+
+func TestWorkerCritical(t *testing.T) {
+	fail := errors.New("lost connection")
+
+	for name, opt := range map[string]WorkerOption{
+		"critical":           WorkerCritical(),
+		"restarts exhausted": WorkerRestart(time.Millisecond, time.Millisecond, 2),
+	} {
+		opt := opt
+
+		t.Run(name, func(t *testing.T) {
+			s, e := InitServer()
+			require.Nil(t, e)
+
+			require.Nil(t, s.StartWorker("db", func(ctx context.Context) error {
+				if e := s.WaitReady(ctx); e != nil {
+					return e
+				}
+
+				return fail
+			}, opt))
+
+			e = s.Serve(context.Background(), Address{Addr: freeAddr(t)})
+
+			var we *WorkerError
+
+			require.ErrorAs(t, e, &we)
+			assert.Equal(t, "db", we.Name)
+			assert.ErrorIs(t, e, fail)
+			assert.Equal(t, WorkerFailed, waitWorker(t, s, "db", WorkerFailed).State)
+		})
+	}
+}